@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxBacktrace limits the number of frames the REPL prints
+const maxBacktrace = 20
+
+// Position of a datum in its source
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// String formats the position as file:line:column
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// callFrame is a procedure call in progress
+type callFrame struct {
+	Name string
+	Pos  *Position
+}
+
+// String formats the frame as it is shown in a backtrace
+func (f callFrame) String() string {
+	if f.Pos == nil {
+		return f.Name
+	}
+
+	return fmt.Sprintf("%s (%s)", f.Name, f.Pos)
+}
+
+// callStack holds the calls being evaluated, tail calls replace the frame of
+// their caller
+var callStack []callFrame
+
+// formPos is where the innermost form or atom being evaluated was read,
+// atoms are interned so their position is kept by the pair holding them
+var formPos *Position
+
+// evalAt evaluates obj read at pos, an atom reports its errors there
+func evalAt(obj Object, pos *Position, env *Environment) Object {
+	if _, ok := obj.(*Pair); ok || pos == nil {
+		return Eval(obj, env)
+	}
+
+	outer := formPos
+	formPos = pos
+	result := Eval(obj, env)
+	formPos = outer
+
+	return result
+}
+
+// evalTail returns the car of cell to be evaluated as a tail call, an atom
+// is evaluated right away so its errors report where it was read
+func evalTail(cell *Pair, env *Environment) (Object, *Environment) {
+	if _, ok := cell.Car.(*Pair); ok || cell.CarPos == nil {
+		return cell.Car, env
+	}
+
+	return evalAt(cell.Car, cell.CarPos, env), nil
+}
+
+// atomError creates an error raised by the atom name, such as an unbound
+// identifier, located where it was read or at the enclosing form
+func atomError(name string, msg string) *Error {
+	err := newError(msg)
+	if formPos != nil {
+		err.Backtrace = append([]callFrame{{Name: name, Pos: formPos}}, err.Backtrace...)
+	}
+
+	return err
+}
+
+// backtrace copies the call stack innermost call first
+func backtrace() []callFrame {
+	frames := make([]callFrame, len(callStack))
+	for idx, frame := range callStack {
+		frames[len(callStack)-1-idx] = frame
+	}
+
+	return frames
+}
+
+// procedureName prefers the name a procedure was defined with
+func procedureName(proc Object, name string) string {
+	switch fn := proc.(type) {
+	case *Lambda:
+		if fn.Name != "" {
+			return fn.Name
+		}
+	case *CaseLambda:
+		if fn.Name != "" {
+			return fn.Name
+		}
+	}
+
+	return name
+}
+
+// Trace formats the backtrace of the error one frame per line
+func (e *Error) Trace() string {
+	lines := []string{}
+
+	for idx, frame := range e.Backtrace {
+		if idx == maxBacktrace {
+			lines = append(lines, fmt.Sprintf("  ... %d more", len(e.Backtrace)-maxBacktrace))
+			break
+		}
+
+		lines = append(lines, "  at "+frame.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -1,333 +1,2842 @@
 package main
 
-import "fmt"
-import "reflect"
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
 
 var scopedBuiltins = map[string]*ScopedBuiltin{}
 
 var builtins = map[string]*Builtin{
 	"+": &Builtin{
 		Fn: func(args ...Object) Object {
-			switch obj := args[0].(type) {
-			case *Integer:
-				for _, rightSide := range args[1:len(args)] {
-					if intArg, ok := rightSide.(*Integer); ok {
-						obj.Value += intArg.Value
-					} else {
-						return newError("Expecting an Integer")
-					}
-				}
+			return foldNumbers('+', &Integer{Value: 0}, args)
+		},
+	},
+	"-": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("- expecting at least one argument")
+			}
 
-				return obj
-			case *Float:
-				for _, rightSide := range args[1:len(args)] {
-					if floatArg, ok := rightSide.(*Float); ok {
-						obj.Value += floatArg.Value
-					} else {
-						return newError("Expecting a Float")
-					}
-				}
+			if len(args) == 1 {
+				return foldNumbers('-', &Integer{Value: 0}, args)
+			}
+
+			return foldNumbers('-', args[0], args[1:])
+		},
+	},
+	"*": &Builtin{
+		Fn: func(args ...Object) Object {
+			return foldNumbers('*', &Integer{Value: 1}, args)
+		},
+	},
+	"/": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("/ expecting at least one argument")
+			}
 
-				return obj
-			default:
-				return errorObject(fmt.Errorf("Unexpected %s expecting one of Integer or Float", obj.Inspect()))
+			if len(args) == 1 {
+				return foldNumbers('/', &Integer{Value: 1}, args)
 			}
+
+			return foldNumbers('/', args[0], args[1:])
 		},
 	},
-	"-": &Builtin{
+	"<": &Builtin{
 		Fn: func(args ...Object) Object {
-			switch obj := args[0].(type) {
-			case *Integer:
-				for _, rightSide := range args[1:len(args)] {
-					if intArg, ok := rightSide.(*Integer); ok {
-						obj.Value -= intArg.Value
-					} else {
-						return newError("Expecting an Integer")
-					}
-				}
+			return compareNumbers(args, func(cmp int) bool { return cmp < 0 })
+		},
+	},
+	"<=": &Builtin{
+		Fn: func(args ...Object) Object {
+			return compareNumbers(args, func(cmp int) bool { return cmp <= 0 })
+		},
+	},
+	">": &Builtin{
+		Fn: func(args ...Object) Object {
+			return compareNumbers(args, func(cmp int) bool { return cmp > 0 })
+		},
+	},
+	">=": &Builtin{
+		Fn: func(args ...Object) Object {
+			return compareNumbers(args, func(cmp int) bool { return cmp >= 0 })
+		},
+	},
+	"=": &Builtin{
+		Fn: func(args ...Object) Object {
+			return compareNumbers(args, func(cmp int) bool { return cmp == 0 })
+		},
+	},
+	"NUMBER?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("NUMBER?", args, 1); err != nil {
+				return err
+			}
 
-				return obj
-			case *Float:
-				for _, rightSide := range args[1:len(args)] {
-					if floatArg, ok := rightSide.(*Float); ok {
-						obj.Value -= floatArg.Value
-					} else {
-						return newError("Expecting a Float")
-					}
-				}
+			return toBoolean(isNumber(args[0]))
+		},
+	},
+	"INTEGER?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("INTEGER?", args, 1); err != nil {
+				return err
+			}
+
+			return toBoolean(isInteger(args[0]))
+		},
+	},
+	"RATIONAL?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("RATIONAL?", args, 1); err != nil {
+				return err
+			}
 
-				return obj
-			default:
-				return errorObject(fmt.Errorf("Unexpected %s expecting one of Integer or Float", obj.Inspect()))
+			if f, ok := args[0].(*Float); ok {
+				return toBoolean(!math.IsInf(f.Value, 0) && !math.IsNaN(f.Value))
 			}
+
+			return toBoolean(isNumber(args[0]))
 		},
 	},
-	"*": &Builtin{
+	"EXACT-INTEGER?": &Builtin{
 		Fn: func(args ...Object) Object {
-			switch obj := args[0].(type) {
-			case *Integer:
-				for _, rightSide := range args[1:len(args)] {
-					if intArg, ok := rightSide.(*Integer); ok {
-						obj.Value *= intArg.Value
-					} else {
-						return newError("Expecting an Integer")
-					}
-				}
+			if err := expectArgs("EXACT-INTEGER?", args, 1); err != nil {
+				return err
+			}
 
-				return obj
-			case *Float:
-				for _, rightSide := range args[1:len(args)] {
-					if floatArg, ok := rightSide.(*Float); ok {
-						obj.Value *= floatArg.Value
-					} else {
-						return newError("Expecting a Float")
-					}
-				}
+			return toBoolean(isExactInteger(args[0]))
+		},
+	},
+	"EXACT?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectNumbers("EXACT?", args, 1); err != nil {
+				return err
+			}
+
+			return toBoolean(isExact(args[0]))
+		},
+	},
+	"INEXACT?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectNumbers("INEXACT?", args, 1); err != nil {
+				return err
+			}
 
-				return obj
-			default:
-				return errorObject(fmt.Errorf("Unexpected %s expecting one of Integer or Float", obj.Inspect()))
+			return toBoolean(!isExact(args[0]))
+		},
+	},
+	"EXACT": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectNumbers("EXACT", args, 1); err != nil {
+				return err
 			}
+
+			return toExact(args[0])
 		},
 	},
-	"/": &Builtin{
+	"INEXACT": &Builtin{
 		Fn: func(args ...Object) Object {
-			switch obj := args[0].(type) {
-			case *Integer:
-				for _, rightSide := range args[1:len(args)] {
-					if intArg, ok := rightSide.(*Integer); ok {
-						obj.Value /= intArg.Value
-					} else {
-						return newError("Expecting an Integer")
-					}
-				}
+			if err := expectNumbers("INEXACT", args, 1); err != nil {
+				return err
+			}
 
-				return obj
-			case *Float:
-				for _, rightSide := range args[1:len(args)] {
-					if floatArg, ok := rightSide.(*Float); ok {
-						obj.Value /= floatArg.Value
-					} else {
-						return newError("Expecting a Float")
-					}
-				}
+			return &Float{Value: toFloat(args[0])}
+		},
+	},
+	"NUMERATOR": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectNumbers("NUMERATOR", args, 1); err != nil {
+				return err
+			}
+
+			exact := toExact(args[0])
+			if isError(exact) {
+				return exact
+			}
 
-				return obj
-			default:
-				return errorObject(fmt.Errorf("Unexpected %s expecting one of Integer or Float", obj.Inspect()))
+			num := normalizeBig(new(big.Int).Set(toRat(exact).Num()))
+			if !isExact(args[0]) {
+				return &Float{Value: toFloat(num)}
 			}
+
+			return num
 		},
 	},
-	"<": &Builtin{
+	"DENOMINATOR": &Builtin{
 		Fn: func(args ...Object) Object {
-			switch obj := args[0].(type) {
-			case *Integer:
-				for _, rightSide := range args[1:len(args)] {
-					if intArg, ok := rightSide.(*Integer); ok {
-						if !(obj.Value < intArg.Value) {
-							return FALSE
-						}
-					} else {
-						return newError("Expecting an Integer")
-					}
-				}
+			if err := expectNumbers("DENOMINATOR", args, 1); err != nil {
+				return err
+			}
 
-				return TRUE
-			case *Float:
-				for _, rightSide := range args[1:len(args)] {
-					if floatArg, ok := rightSide.(*Float); ok {
-						if !(obj.Value < floatArg.Value) {
-							return FALSE
-						}
-					} else {
-						return newError("Expecting a Float")
-					}
-				}
+			exact := toExact(args[0])
+			if isError(exact) {
+				return exact
+			}
 
-				return TRUE
-			default:
-				return errorObject(fmt.Errorf("Unexpected %s expecting one of Integer or Float", obj.Inspect()))
+			den := normalizeBig(new(big.Int).Set(toRat(exact).Denom()))
+			if !isExact(args[0]) {
+				return &Float{Value: toFloat(den)}
 			}
+
+			return den
 		},
 	},
-	"<=": &Builtin{
+	"FLOOR/": &Builtin{
 		Fn: func(args ...Object) Object {
-			switch obj := args[0].(type) {
-			case *Integer:
-				for _, rightSide := range args[1:len(args)] {
-					if intArg, ok := rightSide.(*Integer); ok {
-						if !(obj.Value <= intArg.Value) {
-							return FALSE
-						}
-					} else {
-						return newError("Expecting an Integer")
-					}
-				}
+			quotient, remainder, err := integerDivision(true, args)
+			if err != nil {
+				return err
+			}
 
-				return TRUE
-			case *Float:
-				for _, rightSide := range args[1:len(args)] {
-					if floatArg, ok := rightSide.(*Float); ok {
-						if !(obj.Value <= floatArg.Value) {
-							return FALSE
-						}
-					} else {
-						return newError("Expecting a Float")
-					}
-				}
+			return &Values{Value: []Object{quotient, remainder}}
+		},
+	},
+	"FLOOR-QUOTIENT": &Builtin{
+		Fn: func(args ...Object) Object {
+			quotient, _, err := integerDivision(true, args)
+			if err != nil {
+				return err
+			}
 
-				return TRUE
-			default:
-				return errorObject(fmt.Errorf("Unexpected %s expecting one of Integer or Float", obj.Inspect()))
+			return quotient
+		},
+	},
+	"FLOOR-REMAINDER": &Builtin{
+		Fn: func(args ...Object) Object {
+			_, remainder, err := integerDivision(true, args)
+			if err != nil {
+				return err
 			}
+
+			return remainder
 		},
 	},
-	">": &Builtin{
+	"TRUNCATE/": &Builtin{
 		Fn: func(args ...Object) Object {
-			switch obj := args[0].(type) {
-			case *Integer:
-				for _, rightSide := range args[1:len(args)] {
-					if intArg, ok := rightSide.(*Integer); ok {
-						if !(obj.Value > intArg.Value) {
-							return FALSE
-						}
-					} else {
-						return newError("Expecting an Integer")
-					}
-				}
+			quotient, remainder, err := integerDivision(false, args)
+			if err != nil {
+				return err
+			}
 
-				return TRUE
-			case *Float:
-				for _, rightSide := range args[1:len(args)] {
-					if floatArg, ok := rightSide.(*Float); ok {
-						if !(obj.Value > floatArg.Value) {
-							return FALSE
-						}
-					} else {
-						return newError("Expecting a Float")
-					}
-				}
+			return &Values{Value: []Object{quotient, remainder}}
+		},
+	},
+	"TRUNCATE-QUOTIENT": &Builtin{
+		Fn: func(args ...Object) Object {
+			quotient, _, err := integerDivision(false, args)
+			if err != nil {
+				return err
+			}
 
-				return TRUE
-			default:
-				return errorObject(fmt.Errorf("Unexpected %s expecting one of Integer or Float", obj.Inspect()))
+			return quotient
+		},
+	},
+	"TRUNCATE-REMAINDER": &Builtin{
+		Fn: func(args ...Object) Object {
+			_, remainder, err := integerDivision(false, args)
+			if err != nil {
+				return err
 			}
+
+			return remainder
 		},
 	},
-	">=": &Builtin{
+	"EXACT-INTEGER-SQRT": &Builtin{
 		Fn: func(args ...Object) Object {
-			switch obj := args[0].(type) {
-			case *Integer:
-				for _, rightSide := range args[1:len(args)] {
-					if intArg, ok := rightSide.(*Integer); ok {
-						if !(obj.Value >= intArg.Value) {
-							return FALSE
-						}
-					} else {
-						return newError("Expecting an Integer")
-					}
-				}
+			if err := expectArgs("EXACT-INTEGER-SQRT", args, 1); err != nil {
+				return err
+			}
 
-				return TRUE
-			case *Float:
-				for _, rightSide := range args[1:len(args)] {
-					if floatArg, ok := rightSide.(*Float); ok {
-						if !(obj.Value >= floatArg.Value) {
-							return FALSE
-						}
-					} else {
-						return newError("Expecting a Float")
-					}
-				}
+			if !isExactInteger(args[0]) || toBig(args[0]).Sign() < 0 {
+				return errorObject(fmt.Errorf("Unexpected %s expecting a non-negative exact integer", args[0].Inspect()))
+			}
+
+			n := toBig(args[0])
+			root := new(big.Int).Sqrt(n)
+			rest := new(big.Int).Sub(n, new(big.Int).Mul(root, root))
+
+			return &Values{Value: []Object{normalizeBig(root), normalizeBig(rest)}}
+		},
+	},
+	"VALUES": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 1 {
+				return args[0]
+			}
 
-				return TRUE
-			default:
-				return errorObject(fmt.Errorf("Unexpected %s expecting one of Integer or Float", obj.Inspect()))
+			return &Values{Value: args}
+		},
+	},
+	"CONS": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("CONS", args, 2); err != nil {
+				return err
 			}
+
+			return &Pair{Car: args[0], Cdr: args[1]}
 		},
 	},
-	"=": &Builtin{
+	"CAR": &Builtin{
 		Fn: func(args ...Object) Object {
-			switch obj := args[0].(type) {
-			case *Integer:
-				for _, rightSide := range args[1:len(args)] {
-					if intArg, ok := rightSide.(*Integer); ok {
-						if !(obj.Value == intArg.Value) {
-							return FALSE
-						}
-					} else {
-						return newError("Expecting an Integer")
-					}
-				}
+			pair, err := expectPair("CAR", args)
+			if err != nil {
+				return err
+			}
+
+			return pair.Car
+		},
+	},
+	"CDR": &Builtin{
+		Fn: func(args ...Object) Object {
+			pair, err := expectPair("CDR", args)
+			if err != nil {
+				return err
+			}
+
+			return pair.Cdr
+		},
+	},
+	"SET-CAR!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("SET-CAR!", args, 2); err != nil {
+				return err
+			}
+
+			pair, err := expectPair("SET-CAR!", args[:1])
+			if err != nil {
+				return err
+			}
+
+			pair.Car = args[1]
+			return UNSPECIFIED
+		},
+	},
+	"SET-CDR!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("SET-CDR!", args, 2); err != nil {
+				return err
+			}
+
+			pair, err := expectPair("SET-CDR!", args[:1])
+			if err != nil {
+				return err
+			}
+
+			pair.Cdr = args[1]
+			return UNSPECIFIED
+		},
+	},
+	"LIST": &Builtin{
+		Fn: func(args ...Object) Object {
+			return sliceToList(args, NIL)
+		},
+	},
+	"LENGTH": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("LENGTH", args, 1); err != nil {
+				return err
+			}
+
+			if !isList(args[0]) {
+				return errorObject(fmt.Errorf("LENGTH expecting a proper list found %s", args[0].Inspect()))
+			}
+
+			list, _ := listToSlice(args[0])
+			return &Integer{Value: int64(len(list))}
+		},
+	},
+	"APPEND": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return NIL
+			}
 
-				return TRUE
-			case *Float:
-				for _, rightSide := range args[1:len(args)] {
-					if floatArg, ok := rightSide.(*Float); ok {
-						if !(obj.Value == floatArg.Value) {
-							return FALSE
-						}
-					} else {
-						return newError("Expecting a Float")
-					}
+			result := args[len(args)-1]
+			for idx := len(args) - 2; idx >= 0; idx-- {
+				if !isList(args[idx]) {
+					return errorObject(fmt.Errorf("APPEND expecting a proper list found %s", args[idx].Inspect()))
 				}
 
-				return TRUE
-			default:
-				return errorObject(fmt.Errorf("Unexpected %s expecting one of Integer or Float", obj.Inspect()))
+				list, _ := listToSlice(args[idx])
+				result = sliceToList(list, result)
 			}
+
+			return result
 		},
 	},
-	"QUOTE": &Builtin{
+	"REVERSE": &Builtin{
 		Fn: func(args ...Object) Object {
-			return args[0]
+			if err := expectArgs("REVERSE", args, 1); err != nil {
+				return err
+			}
+
+			if !isList(args[0]) {
+				return errorObject(fmt.Errorf("REVERSE expecting a proper list found %s", args[0].Inspect()))
+			}
+
+			var result Object = NIL
+			for obj := args[0]; obj != NIL; obj = obj.(*Pair).Cdr {
+				result = &Pair{Car: obj.(*Pair).Car, Cdr: result}
+			}
+
+			return result
 		},
 	},
-	"CALL-METHOD": &Builtin{
+	"LIST-TAIL": &Builtin{
 		Fn: func(args ...Object) Object {
-			// methName := args[0].(*String)
-			// obj := args[1]
-			// // params := args[2:len(args)]
-			// values := reflect.ValueOf(obj).MethodByName(methName.Value).Call([]reflect.Value{})
+			if err := expectArgs("LIST-TAIL", args, 2); err != nil {
+				return err
+			}
 
-			// return &String{Value: values[0].String()}
-			if methodName, ok := args[0].(*String); ok {
-				obj := args[1]
-				values := reflect.ValueOf(obj).MethodByName(methodName.Value).Call([]reflect.Value{})
-				return &String{Value: values[0].String()}
+			return listTail("LIST-TAIL", args[0], args[1])
+		},
+	},
+	"LIST-REF": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("LIST-REF", args, 2); err != nil {
+				return err
 			}
 
-			return newError("Expecting a string as the first argument")
+			tail := listTail("LIST-REF", args[0], args[1])
+			if isError(tail) {
+				return tail
+			}
+
+			pair, ok := tail.(*Pair)
+			if !ok {
+				return errorObject(fmt.Errorf("LIST-REF index %s out of range", args[1].Inspect()))
+			}
+
+			return pair.Car
 		},
 	},
-	"CALL-FIELD": &Builtin{
+	"NULL?": &Builtin{
 		Fn: func(args ...Object) Object {
-			if fieldName, ok := args[0].(*String); ok {
-				obj := args[1]
+			if err := expectArgs("NULL?", args, 1); err != nil {
+				return err
+			}
 
-				value := reflect.ValueOf(obj).FieldByName(fieldName.Value)
-				return &String{Value: value.String()}
+			return toBoolean(args[0] == NIL)
+		},
+	},
+	"PAIR?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("PAIR?", args, 1); err != nil {
+				return err
 			}
 
-			return newError("Expecting a string as the first argument")
+			_, ok := args[0].(*Pair)
+			return toBoolean(ok)
 		},
 	},
-}
+	"LIST?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("LIST?", args, 1); err != nil {
+				return err
+			}
 
-func loadScopedBuiltins() {
-	eval := &ScopedBuiltin{
-		Fn: func(env *Environment, args ...Object) Object {
-			r := NewReader(args[0].(*Data).Value)
-			return Eval(r.Read(), env)
+			return toBoolean(isList(args[0]))
 		},
-	}
+	},
+	"EQ?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("EQ?", args, 2); err != nil {
+				return err
+			}
 
-	env := &ScopedBuiltin{
-		Fn: func(env *Environment, args ...Object) Object {
-			return env
+			return toBoolean(isEq(args[0], args[1]))
 		},
-	}
+	},
+	"EQV?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("EQV?", args, 2); err != nil {
+				return err
+			}
 
-	scopedBuiltins["EVAL"] = eval
-	scopedBuiltins["ENV"] = env
+			return toBoolean(isEqv(args[0], args[1]))
+		},
+	},
+	"EQUAL?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("EQUAL?", args, 2); err != nil {
+				return err
+			}
+
+			return toBoolean(isEqual(args[0], args[1]))
+		},
+	},
+	"STRING?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("STRING?", args, 1); err != nil {
+				return err
+			}
+
+			return toBoolean(isString(args[0]))
+		},
+	},
+	"CHAR?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("CHAR?", args, 1); err != nil {
+				return err
+			}
+
+			_, ok := args[0].(*Char)
+			return toBoolean(ok)
+		},
+	},
+	"SYMBOL?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("SYMBOL?", args, 1); err != nil {
+				return err
+			}
+
+			_, ok := args[0].(*Identifier)
+			return toBoolean(ok)
+		},
+	},
+	"SYMBOL->STRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("SYMBOL->STRING", args, 1); err != nil {
+				return err
+			}
+
+			ident, ok := args[0].(*Identifier)
+			if !ok {
+				return errorObject(fmt.Errorf("SYMBOL->STRING expecting a symbol found %s", args[0].Inspect()))
+			}
+
+			return &String{Value: ident.Value}
+		},
+	},
+	"STRING->SYMBOL": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("STRING->SYMBOL", args, 1); err != nil {
+				return err
+			}
+
+			str, err := expectString("STRING->SYMBOL", args[0])
+			if err != nil {
+				return err
+			}
+
+			return intern(str.Value)
+		},
+	},
+	"NUMBER->STRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return errorObject(fmt.Errorf("NUMBER->STRING expecting 1 or 2 arguments got %d", len(args)))
+			}
+
+			if !isNumber(args[0]) {
+				return notANumber(args[0])
+			}
+
+			radix, err := expectRadix("NUMBER->STRING", args[1:])
+			if err != nil {
+				return err
+			}
+
+			if radix == 10 {
+				return &String{Value: args[0].Inspect()}
+			}
+
+			if !isExact(args[0]) {
+				return errorObject(fmt.Errorf("NUMBER->STRING expecting an exact number for radix %d found %s", radix, args[0].Inspect()))
+			}
+
+			rat := toRat(args[0])
+			if rat.IsInt() {
+				return &String{Value: rat.Num().Text(radix)}
+			}
+
+			return &String{Value: rat.Num().Text(radix) + "/" + rat.Denom().Text(radix)}
+		},
+	},
+	"STRING->NUMBER": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return errorObject(fmt.Errorf("STRING->NUMBER expecting 1 or 2 arguments got %d", len(args)))
+			}
+
+			str, err := expectString("STRING->NUMBER", args[0])
+			if err != nil {
+				return err
+			}
+
+			radix, err := expectRadix("STRING->NUMBER", args[1:])
+			if err != nil {
+				return err
+			}
+
+			prefixes := map[int]string{2: "#b", 8: "#o", 10: "", 16: "#x"}
+			if num := parseNumber(prefixes[radix] + str.Value); num != nil {
+				return num
+			}
+
+			return FALSE
+		},
+	},
+	"MAKE-STRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return errorObject(fmt.Errorf("MAKE-STRING expecting 1 or 2 arguments got %d", len(args)))
+			}
+
+			length, err := expectIndex("MAKE-STRING", args[0])
+			if err != nil {
+				return err
+			}
+
+			fill := ' '
+			if len(args) == 2 {
+				char, err := expectChar("MAKE-STRING", args[1])
+				if err != nil {
+					return err
+				}
+
+				fill = char.Value
+			}
+
+			return &String{Value: strings.Repeat(string(fill), length)}
+		},
+	},
+	"STRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			runes := []rune{}
+
+			for _, arg := range args {
+				char, err := expectChar("STRING", arg)
+				if err != nil {
+					return err
+				}
+
+				runes = append(runes, char.Value)
+			}
+
+			return &String{Value: string(runes)}
+		},
+	},
+	"STRING-LENGTH": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("STRING-LENGTH", args, 1); err != nil {
+				return err
+			}
+
+			str, err := expectString("STRING-LENGTH", args[0])
+			if err != nil {
+				return err
+			}
+
+			return &Integer{Value: int64(utf8.RuneCountInString(str.Value))}
+		},
+	},
+	"STRING-REF": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("STRING-REF", args, 2); err != nil {
+				return err
+			}
+
+			str, err := expectString("STRING-REF", args[0])
+			if err != nil {
+				return err
+			}
+
+			runes := []rune(str.Value)
+			index, err := expectBounds("STRING-REF", args[1], len(runes))
+			if err != nil {
+				return err
+			}
+
+			return &Char{Value: runes[index]}
+		},
+	},
+	"STRING-SET!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("STRING-SET!", args, 3); err != nil {
+				return err
+			}
+
+			str, err := expectString("STRING-SET!", args[0])
+			if err != nil {
+				return err
+			}
+
+			runes := []rune(str.Value)
+			index, err := expectBounds("STRING-SET!", args[1], len(runes))
+			if err != nil {
+				return err
+			}
+
+			char, err := expectChar("STRING-SET!", args[2])
+			if err != nil {
+				return err
+			}
+
+			runes[index] = char.Value
+			str.Value = string(runes)
+
+			return UNSPECIFIED
+		},
+	},
+	"SUBSTRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("SUBSTRING", args, 3); err != nil {
+				return err
+			}
+
+			str, err := expectString("SUBSTRING", args[0])
+			if err != nil {
+				return err
+			}
+
+			runes := []rune(str.Value)
+			start, end, err := expectRange("SUBSTRING", args[1:], len(runes))
+			if err != nil {
+				return err
+			}
+
+			return &String{Value: string(runes[start:end])}
+		},
+	},
+	"STRING-APPEND": &Builtin{
+		Fn: func(args ...Object) Object {
+			appended := strings.Builder{}
+
+			for _, arg := range args {
+				str, err := expectString("STRING-APPEND", arg)
+				if err != nil {
+					return err
+				}
+
+				appended.WriteString(str.Value)
+			}
+
+			return &String{Value: appended.String()}
+		},
+	},
+	"STRING-COPY": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("STRING-COPY expecting a string")
+			}
+
+			str, err := expectString("STRING-COPY", args[0])
+			if err != nil {
+				return err
+			}
+
+			runes := []rune(str.Value)
+			start, end, err := expectRange("STRING-COPY", args[1:], len(runes))
+			if err != nil {
+				return err
+			}
+
+			return &String{Value: string(runes[start:end])}
+		},
+	},
+	"STRING-COPY!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 3 {
+				return newError("STRING-COPY! expecting (STRING-COPY! to at from [start [end]])")
+			}
+
+			to, err := expectString("STRING-COPY!", args[0])
+			if err != nil {
+				return err
+			}
+
+			at, err := expectIndex("STRING-COPY!", args[1])
+			if err != nil {
+				return err
+			}
+
+			from, err := expectString("STRING-COPY!", args[2])
+			if err != nil {
+				return err
+			}
+
+			source, target := []rune(from.Value), []rune(to.Value)
+			start, end, err := expectRange("STRING-COPY!", args[3:], len(source))
+			if err != nil {
+				return err
+			}
+
+			if at+end-start > len(target) {
+				return errorObject(fmt.Errorf("STRING-COPY! copying %d characters at %d overflows length %d", end-start, at, len(target)))
+			}
+
+			copy(target[at:], source[start:end])
+			to.Value = string(target)
+
+			return UNSPECIFIED
+		},
+	},
+	"STRING-FILL!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 2 {
+				return newError("STRING-FILL! expecting (STRING-FILL! string char [start [end]])")
+			}
+
+			str, err := expectString("STRING-FILL!", args[0])
+			if err != nil {
+				return err
+			}
+
+			char, err := expectChar("STRING-FILL!", args[1])
+			if err != nil {
+				return err
+			}
+
+			runes := []rune(str.Value)
+			start, end, err := expectRange("STRING-FILL!", args[2:], len(runes))
+			if err != nil {
+				return err
+			}
+
+			for idx := start; idx < end; idx++ {
+				runes[idx] = char.Value
+			}
+
+			str.Value = string(runes)
+			return UNSPECIFIED
+		},
+	},
+	"STRING->LIST": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("STRING->LIST expecting a string")
+			}
+
+			str, err := expectString("STRING->LIST", args[0])
+			if err != nil {
+				return err
+			}
+
+			runes := []rune(str.Value)
+			start, end, err := expectRange("STRING->LIST", args[1:], len(runes))
+			if err != nil {
+				return err
+			}
+
+			chars := []Object{}
+			for _, char := range runes[start:end] {
+				chars = append(chars, &Char{Value: char})
+			}
+
+			return sliceToList(chars, NIL)
+		},
+	},
+	"LIST->STRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("LIST->STRING", args, 1); err != nil {
+				return err
+			}
+
+			chars, err := listToSlice(args[0])
+			if err != nil {
+				return err
+			}
+
+			runes := []rune{}
+			for _, obj := range chars {
+				char, err := expectChar("LIST->STRING", obj)
+				if err != nil {
+					return err
+				}
+
+				runes = append(runes, char.Value)
+			}
+
+			return &String{Value: string(runes)}
+		},
+	},
+	"STRING-UPCASE":    stringMapping("STRING-UPCASE", strings.ToUpper),
+	"STRING-DOWNCASE":  stringMapping("STRING-DOWNCASE", strings.ToLower),
+	"STRING-FOLDCASE":  stringMapping("STRING-FOLDCASE", foldString),
+	"STRING=?":         stringComparison("STRING=?", false, func(c int) bool { return c == 0 }),
+	"STRING<?":         stringComparison("STRING<?", false, func(c int) bool { return c < 0 }),
+	"STRING>?":         stringComparison("STRING>?", false, func(c int) bool { return c > 0 }),
+	"STRING<=?":        stringComparison("STRING<=?", false, func(c int) bool { return c <= 0 }),
+	"STRING>=?":        stringComparison("STRING>=?", false, func(c int) bool { return c >= 0 }),
+	"STRING-CI=?":      stringComparison("STRING-CI=?", true, func(c int) bool { return c == 0 }),
+	"STRING-CI<?":      stringComparison("STRING-CI<?", true, func(c int) bool { return c < 0 }),
+	"STRING-CI>?":      stringComparison("STRING-CI>?", true, func(c int) bool { return c > 0 }),
+	"STRING-CI<=?":     stringComparison("STRING-CI<=?", true, func(c int) bool { return c <= 0 }),
+	"STRING-CI>=?":     stringComparison("STRING-CI>=?", true, func(c int) bool { return c >= 0 }),
+	"CHAR=?":           charComparison("CHAR=?", false, func(c int) bool { return c == 0 }),
+	"CHAR<?":           charComparison("CHAR<?", false, func(c int) bool { return c < 0 }),
+	"CHAR>?":           charComparison("CHAR>?", false, func(c int) bool { return c > 0 }),
+	"CHAR<=?":          charComparison("CHAR<=?", false, func(c int) bool { return c <= 0 }),
+	"CHAR>=?":          charComparison("CHAR>=?", false, func(c int) bool { return c >= 0 }),
+	"CHAR-CI=?":        charComparison("CHAR-CI=?", true, func(c int) bool { return c == 0 }),
+	"CHAR-CI<?":        charComparison("CHAR-CI<?", true, func(c int) bool { return c < 0 }),
+	"CHAR-CI>?":        charComparison("CHAR-CI>?", true, func(c int) bool { return c > 0 }),
+	"CHAR-CI<=?":       charComparison("CHAR-CI<=?", true, func(c int) bool { return c <= 0 }),
+	"CHAR-CI>=?":       charComparison("CHAR-CI>=?", true, func(c int) bool { return c >= 0 }),
+	"CHAR-UPCASE":      charMapping("CHAR-UPCASE", unicode.ToUpper),
+	"CHAR-DOWNCASE":    charMapping("CHAR-DOWNCASE", unicode.ToLower),
+	"CHAR-FOLDCASE":    charMapping("CHAR-FOLDCASE", foldRune),
+	"CHAR-ALPHABETIC?": charPredicate("CHAR-ALPHABETIC?", unicode.IsLetter),
+	"CHAR-NUMERIC?":    charPredicate("CHAR-NUMERIC?", unicode.IsDigit),
+	"CHAR-WHITESPACE?": charPredicate("CHAR-WHITESPACE?", unicode.IsSpace),
+	"CHAR-UPPER-CASE?": charPredicate("CHAR-UPPER-CASE?", unicode.IsUpper),
+	"CHAR-LOWER-CASE?": charPredicate("CHAR-LOWER-CASE?", unicode.IsLower),
+	"CHAR->INTEGER": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("CHAR->INTEGER", args, 1); err != nil {
+				return err
+			}
+
+			char, err := expectChar("CHAR->INTEGER", args[0])
+			if err != nil {
+				return err
+			}
+
+			return &Integer{Value: int64(char.Value)}
+		},
+	},
+	"INTEGER->CHAR": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("INTEGER->CHAR", args, 1); err != nil {
+				return err
+			}
+
+			code, ok := args[0].(*Integer)
+			if !ok || code.Value > utf8.MaxRune || !utf8.ValidRune(rune(code.Value)) {
+				return errorObject(fmt.Errorf("INTEGER->CHAR expecting a unicode scalar value found %s", args[0].Inspect()))
+			}
+
+			return &Char{Value: rune(code.Value)}
+		},
+	},
+	"DIGIT-VALUE": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("DIGIT-VALUE", args, 1); err != nil {
+				return err
+			}
+
+			char, err := expectChar("DIGIT-VALUE", args[0])
+			if err != nil {
+				return err
+			}
+
+			if !unicode.IsDigit(char.Value) {
+				return FALSE
+			}
+
+			for zero := char.Value; ; zero-- {
+				if !unicode.IsDigit(zero - 1) {
+					return &Integer{Value: int64(char.Value-zero) % 10}
+				}
+			}
+		},
+	},
+	"VECTOR?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("VECTOR?", args, 1); err != nil {
+				return err
+			}
+
+			_, ok := args[0].(*Vector)
+			return toBoolean(ok)
+		},
+	},
+	"MAKE-VECTOR": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return errorObject(fmt.Errorf("MAKE-VECTOR expecting 1 or 2 arguments got %d", len(args)))
+			}
+
+			length, err := expectIndex("MAKE-VECTOR", args[0])
+			if err != nil {
+				return err
+			}
+
+			var fill Object = &Integer{Value: 0}
+			if len(args) == 2 {
+				fill = args[1]
+			}
+
+			items := make([]Object, length)
+			for idx := range items {
+				items[idx] = fill
+			}
+
+			return &Vector{Value: items}
+		},
+	},
+	"VECTOR": &Builtin{
+		Fn: func(args ...Object) Object {
+			return &Vector{Value: append([]Object{}, args...)}
+		},
+	},
+	"VECTOR-LENGTH": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("VECTOR-LENGTH", args, 1); err != nil {
+				return err
+			}
+
+			vector, err := expectVector("VECTOR-LENGTH", args[0])
+			if err != nil {
+				return err
+			}
+
+			return &Integer{Value: int64(len(vector.Value))}
+		},
+	},
+	"VECTOR-REF": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("VECTOR-REF", args, 2); err != nil {
+				return err
+			}
+
+			vector, err := expectVector("VECTOR-REF", args[0])
+			if err != nil {
+				return err
+			}
+
+			index, err := expectBounds("VECTOR-REF", args[1], len(vector.Value))
+			if err != nil {
+				return err
+			}
+
+			return vector.Value[index]
+		},
+	},
+	"VECTOR-SET!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("VECTOR-SET!", args, 3); err != nil {
+				return err
+			}
+
+			vector, err := expectMutableVector("VECTOR-SET!", args[0])
+			if err != nil {
+				return err
+			}
+
+			index, err := expectBounds("VECTOR-SET!", args[1], len(vector.Value))
+			if err != nil {
+				return err
+			}
+
+			vector.Value[index] = args[2]
+			return UNSPECIFIED
+		},
+	},
+	"VECTOR->LIST": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("VECTOR->LIST expecting a vector")
+			}
+
+			vector, err := expectVector("VECTOR->LIST", args[0])
+			if err != nil {
+				return err
+			}
+
+			start, end, err := expectRange("VECTOR->LIST", args[1:], len(vector.Value))
+			if err != nil {
+				return err
+			}
+
+			return sliceToList(vector.Value[start:end], NIL)
+		},
+	},
+	"LIST->VECTOR": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("LIST->VECTOR", args, 1); err != nil {
+				return err
+			}
+
+			items, err := listToSlice(args[0])
+			if err != nil {
+				return err
+			}
+
+			return &Vector{Value: items}
+		},
+	},
+	"VECTOR-FILL!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 2 {
+				return newError("VECTOR-FILL! expecting (VECTOR-FILL! vector fill [start [end]])")
+			}
+
+			vector, err := expectMutableVector("VECTOR-FILL!", args[0])
+			if err != nil {
+				return err
+			}
+
+			start, end, err := expectRange("VECTOR-FILL!", args[2:], len(vector.Value))
+			if err != nil {
+				return err
+			}
+
+			for idx := start; idx < end; idx++ {
+				vector.Value[idx] = args[1]
+			}
+
+			return UNSPECIFIED
+		},
+	},
+	"VECTOR-COPY": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("VECTOR-COPY expecting a vector")
+			}
+
+			vector, err := expectVector("VECTOR-COPY", args[0])
+			if err != nil {
+				return err
+			}
+
+			start, end, err := expectRange("VECTOR-COPY", args[1:], len(vector.Value))
+			if err != nil {
+				return err
+			}
+
+			return &Vector{Value: append([]Object{}, vector.Value[start:end]...)}
+		},
+	},
+	"VECTOR-COPY!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 3 {
+				return newError("VECTOR-COPY! expecting (VECTOR-COPY! to at from [start [end]])")
+			}
+
+			to, err := expectMutableVector("VECTOR-COPY!", args[0])
+			if err != nil {
+				return err
+			}
+
+			at, err := expectIndex("VECTOR-COPY!", args[1])
+			if err != nil {
+				return err
+			}
+
+			from, err := expectVector("VECTOR-COPY!", args[2])
+			if err != nil {
+				return err
+			}
+
+			start, end, err := expectRange("VECTOR-COPY!", args[3:], len(from.Value))
+			if err != nil {
+				return err
+			}
+
+			if at+end-start > len(to.Value) {
+				return errorObject(fmt.Errorf("VECTOR-COPY! copying %d elements at %d overflows length %d", end-start, at, len(to.Value)))
+			}
+
+			copy(to.Value[at:], from.Value[start:end])
+			return UNSPECIFIED
+		},
+	},
+	"VECTOR-APPEND": &Builtin{
+		Fn: func(args ...Object) Object {
+			items := []Object{}
+
+			for _, arg := range args {
+				vector, err := expectVector("VECTOR-APPEND", arg)
+				if err != nil {
+					return err
+				}
+
+				items = append(items, vector.Value...)
+			}
+
+			return &Vector{Value: items}
+		},
+	},
+	"BYTEVECTOR?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("BYTEVECTOR?", args, 1); err != nil {
+				return err
+			}
+
+			_, ok := args[0].(*Bytevector)
+			return toBoolean(ok)
+		},
+	},
+	"MAKE-BYTEVECTOR": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return errorObject(fmt.Errorf("MAKE-BYTEVECTOR expecting 1 or 2 arguments got %d", len(args)))
+			}
+
+			length, err := expectIndex("MAKE-BYTEVECTOR", args[0])
+			if err != nil {
+				return err
+			}
+
+			fill := byte(0)
+			if len(args) == 2 {
+				if fill, err = expectByte("MAKE-BYTEVECTOR", args[1]); err != nil {
+					return err
+				}
+			}
+
+			return &Bytevector{Value: bytes.Repeat([]byte{fill}, length)}
+		},
+	},
+	"BYTEVECTOR": &Builtin{
+		Fn: func(args ...Object) Object {
+			values := []byte{}
+
+			for _, arg := range args {
+				value, err := expectByte("BYTEVECTOR", arg)
+				if err != nil {
+					return err
+				}
+
+				values = append(values, value)
+			}
+
+			return &Bytevector{Value: values}
+		},
+	},
+	"BYTEVECTOR-LENGTH": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("BYTEVECTOR-LENGTH", args, 1); err != nil {
+				return err
+			}
+
+			bytevector, err := expectBytevector("BYTEVECTOR-LENGTH", args[0])
+			if err != nil {
+				return err
+			}
+
+			return &Integer{Value: int64(len(bytevector.Value))}
+		},
+	},
+	"BYTEVECTOR-U8-REF": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("BYTEVECTOR-U8-REF", args, 2); err != nil {
+				return err
+			}
+
+			bytevector, err := expectBytevector("BYTEVECTOR-U8-REF", args[0])
+			if err != nil {
+				return err
+			}
+
+			index, err := expectBounds("BYTEVECTOR-U8-REF", args[1], len(bytevector.Value))
+			if err != nil {
+				return err
+			}
+
+			return &Integer{Value: int64(bytevector.Value[index])}
+		},
+	},
+	"BYTEVECTOR-U8-SET!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("BYTEVECTOR-U8-SET!", args, 3); err != nil {
+				return err
+			}
+
+			bytevector, err := expectMutableBytevector("BYTEVECTOR-U8-SET!", args[0])
+			if err != nil {
+				return err
+			}
+
+			index, err := expectBounds("BYTEVECTOR-U8-SET!", args[1], len(bytevector.Value))
+			if err != nil {
+				return err
+			}
+
+			value, err := expectByte("BYTEVECTOR-U8-SET!", args[2])
+			if err != nil {
+				return err
+			}
+
+			bytevector.Value[index] = value
+			return UNSPECIFIED
+		},
+	},
+	"BYTEVECTOR-COPY": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("BYTEVECTOR-COPY expecting a bytevector")
+			}
+
+			bytevector, err := expectBytevector("BYTEVECTOR-COPY", args[0])
+			if err != nil {
+				return err
+			}
+
+			start, end, err := expectRange("BYTEVECTOR-COPY", args[1:], len(bytevector.Value))
+			if err != nil {
+				return err
+			}
+
+			return &Bytevector{Value: append([]byte{}, bytevector.Value[start:end]...)}
+		},
+	},
+	"BYTEVECTOR-COPY!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 3 {
+				return newError("BYTEVECTOR-COPY! expecting (BYTEVECTOR-COPY! to at from [start [end]])")
+			}
+
+			to, err := expectMutableBytevector("BYTEVECTOR-COPY!", args[0])
+			if err != nil {
+				return err
+			}
+
+			at, err := expectIndex("BYTEVECTOR-COPY!", args[1])
+			if err != nil {
+				return err
+			}
+
+			from, err := expectBytevector("BYTEVECTOR-COPY!", args[2])
+			if err != nil {
+				return err
+			}
+
+			start, end, err := expectRange("BYTEVECTOR-COPY!", args[3:], len(from.Value))
+			if err != nil {
+				return err
+			}
+
+			if at+end-start > len(to.Value) {
+				return errorObject(fmt.Errorf("BYTEVECTOR-COPY! copying %d bytes at %d overflows length %d", end-start, at, len(to.Value)))
+			}
+
+			copy(to.Value[at:], from.Value[start:end])
+			return UNSPECIFIED
+		},
+	},
+	"BYTEVECTOR-APPEND": &Builtin{
+		Fn: func(args ...Object) Object {
+			values := []byte{}
+
+			for _, arg := range args {
+				bytevector, err := expectBytevector("BYTEVECTOR-APPEND", arg)
+				if err != nil {
+					return err
+				}
+
+				values = append(values, bytevector.Value...)
+			}
+
+			return &Bytevector{Value: values}
+		},
+	},
+	"UTF8->STRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("UTF8->STRING expecting a bytevector")
+			}
+
+			bytevector, err := expectBytevector("UTF8->STRING", args[0])
+			if err != nil {
+				return err
+			}
+
+			start, end, err := expectRange("UTF8->STRING", args[1:], len(bytevector.Value))
+			if err != nil {
+				return err
+			}
+
+			if !utf8.Valid(bytevector.Value[start:end]) {
+				return newError("UTF8->STRING expecting valid UTF-8")
+			}
+
+			return &String{Value: string(bytevector.Value[start:end])}
+		},
+	},
+	"STRING->UTF8": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("STRING->UTF8 expecting a string")
+			}
+
+			str, err := expectString("STRING->UTF8", args[0])
+			if err != nil {
+				return err
+			}
+
+			runes := []rune(str.Value)
+			start, end, err := expectRange("STRING->UTF8", args[1:], len(runes))
+			if err != nil {
+				return err
+			}
+
+			return &Bytevector{Value: []byte(string(runes[start:end]))}
+		},
+	},
+	"BYTEVECTOR-S8-REF":           bytevectorIntRef("BYTEVECTOR-S8-REF", 1, true),
+	"BYTEVECTOR-S8-SET!":          bytevectorIntSet("BYTEVECTOR-S8-SET!", 1, true),
+	"BYTEVECTOR-U16-REF":          bytevectorIntRef("BYTEVECTOR-U16-REF", 2, false),
+	"BYTEVECTOR-S16-REF":          bytevectorIntRef("BYTEVECTOR-S16-REF", 2, true),
+	"BYTEVECTOR-U16-SET!":         bytevectorIntSet("BYTEVECTOR-U16-SET!", 2, false),
+	"BYTEVECTOR-S16-SET!":         bytevectorIntSet("BYTEVECTOR-S16-SET!", 2, true),
+	"BYTEVECTOR-U32-REF":          bytevectorIntRef("BYTEVECTOR-U32-REF", 4, false),
+	"BYTEVECTOR-S32-REF":          bytevectorIntRef("BYTEVECTOR-S32-REF", 4, true),
+	"BYTEVECTOR-U32-SET!":         bytevectorIntSet("BYTEVECTOR-U32-SET!", 4, false),
+	"BYTEVECTOR-S32-SET!":         bytevectorIntSet("BYTEVECTOR-S32-SET!", 4, true),
+	"BYTEVECTOR-U64-REF":          bytevectorIntRef("BYTEVECTOR-U64-REF", 8, false),
+	"BYTEVECTOR-S64-REF":          bytevectorIntRef("BYTEVECTOR-S64-REF", 8, true),
+	"BYTEVECTOR-U64-SET!":         bytevectorIntSet("BYTEVECTOR-U64-SET!", 8, false),
+	"BYTEVECTOR-S64-SET!":         bytevectorIntSet("BYTEVECTOR-S64-SET!", 8, true),
+	"BYTEVECTOR-IEEE-SINGLE-REF":  bytevectorFloatRef("BYTEVECTOR-IEEE-SINGLE-REF", 4),
+	"BYTEVECTOR-IEEE-SINGLE-SET!": bytevectorFloatSet("BYTEVECTOR-IEEE-SINGLE-SET!", 4),
+	"BYTEVECTOR-IEEE-DOUBLE-REF":  bytevectorFloatRef("BYTEVECTOR-IEEE-DOUBLE-REF", 8),
+	"BYTEVECTOR-IEEE-DOUBLE-SET!": bytevectorFloatSet("BYTEVECTOR-IEEE-DOUBLE-SET!", 8),
+	"PORT?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("PORT?", args, 1); err != nil {
+				return err
+			}
+
+			_, ok := args[0].(*Port)
+			return toBoolean(ok)
+		},
+	},
+	"INPUT-PORT?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("INPUT-PORT?", args, 1); err != nil {
+				return err
+			}
+
+			port, ok := args[0].(*Port)
+			return toBoolean(ok && port.reader != nil)
+		},
+	},
+	"OUTPUT-PORT?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("OUTPUT-PORT?", args, 1); err != nil {
+				return err
+			}
+
+			port, ok := args[0].(*Port)
+			return toBoolean(ok && port.writer != nil)
+		},
+	},
+	"TEXTUAL-PORT?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("TEXTUAL-PORT?", args, 1); err != nil {
+				return err
+			}
+
+			port, ok := args[0].(*Port)
+			return toBoolean(ok && !port.binary)
+		},
+	},
+	"BINARY-PORT?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("BINARY-PORT?", args, 1); err != nil {
+				return err
+			}
+
+			port, ok := args[0].(*Port)
+			return toBoolean(ok && port.binary)
+		},
+	},
+	"CURRENT-INPUT-PORT": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("CURRENT-INPUT-PORT", args, 0); err != nil {
+				return err
+			}
+
+			return currentInput
+		},
+	},
+	"CURRENT-OUTPUT-PORT": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("CURRENT-OUTPUT-PORT", args, 0); err != nil {
+				return err
+			}
+
+			return currentOutput
+		},
+	},
+	"CURRENT-ERROR-PORT": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("CURRENT-ERROR-PORT", args, 0); err != nil {
+				return err
+			}
+
+			return currentError
+		},
+	},
+	"DISPLAY": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("DISPLAY expecting an object to display")
+			}
+
+			port, err := expectPort("DISPLAY", args, 1, true, false)
+			if err != nil {
+				return err
+			}
+
+			return port.write(display(args[0]))
+		},
+	},
+	"WRITE": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("WRITE expecting an object to write")
+			}
+
+			port, err := expectPort("WRITE", args, 1, true, false)
+			if err != nil {
+				return err
+			}
+
+			return port.write(args[0].Inspect())
+		},
+	},
+	"WRITE-STRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("WRITE-STRING expecting a string")
+			}
+
+			str, err := expectString("WRITE-STRING", args[0])
+			if err != nil {
+				return err
+			}
+
+			port, err := expectPort("WRITE-STRING", args[:min(len(args), 2)], 1, true, false)
+			if err != nil {
+				return err
+			}
+
+			runes := []rune(str.Value)
+			start, end, err := expectRange("WRITE-STRING", args[min(len(args), 2):], len(runes))
+			if err != nil {
+				return err
+			}
+
+			return port.write(string(runes[start:end]))
+		},
+	},
+	"WRITE-CHAR": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("WRITE-CHAR expecting a character")
+			}
+
+			char, err := expectChar("WRITE-CHAR", args[0])
+			if err != nil {
+				return err
+			}
+
+			port, err := expectPort("WRITE-CHAR", args, 1, true, false)
+			if err != nil {
+				return err
+			}
+
+			return port.write(string(char.Value))
+		},
+	},
+	"NEWLINE": &Builtin{
+		Fn: func(args ...Object) Object {
+			port, err := expectPort("NEWLINE", args, 0, true, false)
+			if err != nil {
+				return err
+			}
+
+			return port.write("\n")
+		},
+	},
+	"FLUSH-OUTPUT-PORT": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) > 1 {
+				return newError("FLUSH-OUTPUT-PORT expecting at most one port")
+			}
+
+			port := currentOutput
+			if len(args) == 1 {
+				output, ok := args[0].(*Port)
+				if !ok || output.writer == nil {
+					return errorObject(fmt.Errorf("FLUSH-OUTPUT-PORT expecting an output port found %s", args[0].Inspect()))
+				}
+
+				port = output
+			}
+
+			if err := port.flush(); err != nil {
+				return err
+			}
+
+			return UNSPECIFIED
+		},
+	},
+	"READ": &Builtin{
+		Fn: func(args ...Object) Object {
+			port, err := expectPort("READ", args, 0, false, false)
+			if err != nil {
+				return err
+			}
+
+			return port.readDatum()
+		},
+	},
+	"READ-CHAR": &Builtin{
+		Fn: func(args ...Object) Object {
+			port, err := expectPort("READ-CHAR", args, 0, false, false)
+			if err != nil {
+				return err
+			}
+
+			return port.readChar()
+		},
+	},
+	"PEEK-CHAR": &Builtin{
+		Fn: func(args ...Object) Object {
+			port, err := expectPort("PEEK-CHAR", args, 0, false, false)
+			if err != nil {
+				return err
+			}
+
+			return port.peekChar()
+		},
+	},
+	"READ-LINE": &Builtin{
+		Fn: func(args ...Object) Object {
+			port, err := expectPort("READ-LINE", args, 0, false, false)
+			if err != nil {
+				return err
+			}
+
+			return port.readLine()
+		},
+	},
+	"READ-STRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("READ-STRING expecting a character count")
+			}
+
+			count, err := expectIndex("READ-STRING", args[0])
+			if err != nil {
+				return err
+			}
+
+			port, err := expectPort("READ-STRING", args, 1, false, false)
+			if err != nil {
+				return err
+			}
+
+			return port.readString(count)
+		},
+	},
+	"CHAR-READY?": &Builtin{
+		Fn: func(args ...Object) Object {
+			port, err := expectPort("CHAR-READY?", args, 0, false, false)
+			if err != nil {
+				return err
+			}
+
+			return toBoolean(port.charReady())
+		},
+	},
+	"EOF-OBJECT": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("EOF-OBJECT", args, 0); err != nil {
+				return err
+			}
+
+			return EOFOBJECT
+		},
+	},
+	"EOF-OBJECT?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("EOF-OBJECT?", args, 1); err != nil {
+				return err
+			}
+
+			return toBoolean(args[0] == EOFOBJECT)
+		},
+	},
+	"CLOSE-PORT":        closePort("CLOSE-PORT", nil),
+	"CLOSE-INPUT-PORT":  closePort("CLOSE-INPUT-PORT", func(port *Port) bool { return port.reader != nil }),
+	"CLOSE-OUTPUT-PORT": closePort("CLOSE-OUTPUT-PORT", func(port *Port) bool { return port.writer != nil }),
+	"OPEN-INPUT-STRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("OPEN-INPUT-STRING", args, 1); err != nil {
+				return err
+			}
+
+			str, err := expectString("OPEN-INPUT-STRING", args[0])
+			if err != nil {
+				return err
+			}
+
+			return newInputString(str.Value)
+		},
+	},
+	"OPEN-OUTPUT-STRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("OPEN-OUTPUT-STRING", args, 0); err != nil {
+				return err
+			}
+
+			return newOutputString()
+		},
+	},
+	"GET-OUTPUT-STRING": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("GET-OUTPUT-STRING", args, 1); err != nil {
+				return err
+			}
+
+			port, ok := args[0].(*Port)
+			if !ok {
+				return errorObject(fmt.Errorf("GET-OUTPUT-STRING expecting an output string port found %s", args[0].Inspect()))
+			}
+
+			return port.outputString("GET-OUTPUT-STRING")
+		},
+	},
+	"OPEN-INPUT-BYTEVECTOR": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("OPEN-INPUT-BYTEVECTOR", args, 1); err != nil {
+				return err
+			}
+
+			bytevector, err := expectBytevector("OPEN-INPUT-BYTEVECTOR", args[0])
+			if err != nil {
+				return err
+			}
+
+			return newInputBytevector(bytevector.Value)
+		},
+	},
+	"OPEN-OUTPUT-BYTEVECTOR": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("OPEN-OUTPUT-BYTEVECTOR", args, 0); err != nil {
+				return err
+			}
+
+			return newOutputBytevector()
+		},
+	},
+	"GET-OUTPUT-BYTEVECTOR": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("GET-OUTPUT-BYTEVECTOR", args, 1); err != nil {
+				return err
+			}
+
+			port, ok := args[0].(*Port)
+			if !ok {
+				return errorObject(fmt.Errorf("GET-OUTPUT-BYTEVECTOR expecting an output bytevector port found %s", args[0].Inspect()))
+			}
+
+			return port.outputBytevector("GET-OUTPUT-BYTEVECTOR")
+		},
+	},
+	"READ-U8": &Builtin{
+		Fn: func(args ...Object) Object {
+			port, err := expectPort("READ-U8", args, 0, false, true)
+			if err != nil {
+				return err
+			}
+
+			return port.readByte()
+		},
+	},
+	"PEEK-U8": &Builtin{
+		Fn: func(args ...Object) Object {
+			port, err := expectPort("PEEK-U8", args, 0, false, true)
+			if err != nil {
+				return err
+			}
+
+			return port.peekByte()
+		},
+	},
+	"U8-READY?": &Builtin{
+		Fn: func(args ...Object) Object {
+			port, err := expectPort("U8-READY?", args, 0, false, true)
+			if err != nil {
+				return err
+			}
+
+			return toBoolean(port.charReady())
+		},
+	},
+	"READ-BYTEVECTOR": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("READ-BYTEVECTOR expecting a count")
+			}
+
+			count, err := expectIndex("READ-BYTEVECTOR", args[0])
+			if err != nil {
+				return err
+			}
+
+			port, err := expectPort("READ-BYTEVECTOR", args, 1, false, true)
+			if err != nil {
+				return err
+			}
+
+			return port.readBytes(count)
+		},
+	},
+	"WRITE-U8": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("WRITE-U8 expecting a byte")
+			}
+
+			value, err := expectByte("WRITE-U8", args[0])
+			if err != nil {
+				return err
+			}
+
+			port, err := expectPort("WRITE-U8", args, 1, true, true)
+			if err != nil {
+				return err
+			}
+
+			return port.write(string([]byte{value}))
+		},
+	},
+	"WRITE-BYTEVECTOR": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("WRITE-BYTEVECTOR expecting a bytevector")
+			}
+
+			bytevector, err := expectBytevector("WRITE-BYTEVECTOR", args[0])
+			if err != nil {
+				return err
+			}
+
+			port, err := expectPort("WRITE-BYTEVECTOR", args[:min(len(args), 2)], 1, true, true)
+			if err != nil {
+				return err
+			}
+
+			start, end := 0, len(bytevector.Value)
+			if len(args) > 2 {
+				start, end, err = expectRange("WRITE-BYTEVECTOR", args[2:], len(bytevector.Value))
+				if err != nil {
+					return err
+				}
+			}
+
+			if _, writeErr := port.writer.Write(bytevector.Value[start:end]); writeErr != nil {
+				return errorObject(writeErr)
+			}
+
+			return UNSPECIFIED
+		},
+	},
+	"OPEN-INPUT-FILE": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("OPEN-INPUT-FILE", args, 1); err != nil {
+				return err
+			}
+
+			port, err := openFile("OPEN-INPUT-FILE", args[0], false, false)
+			if err != nil {
+				return err
+			}
+
+			return port
+		},
+	},
+	"OPEN-OUTPUT-FILE": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("OPEN-OUTPUT-FILE", args, 1); err != nil {
+				return err
+			}
+
+			port, err := openFile("OPEN-OUTPUT-FILE", args[0], true, false)
+			if err != nil {
+				return err
+			}
+
+			return port
+		},
+	},
+	"OPEN-BINARY-INPUT-FILE": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("OPEN-BINARY-INPUT-FILE", args, 1); err != nil {
+				return err
+			}
+
+			port, err := openFile("OPEN-BINARY-INPUT-FILE", args[0], false, true)
+			if err != nil {
+				return err
+			}
+
+			return port
+		},
+	},
+	"OPEN-BINARY-OUTPUT-FILE": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("OPEN-BINARY-OUTPUT-FILE", args, 1); err != nil {
+				return err
+			}
+
+			port, err := openFile("OPEN-BINARY-OUTPUT-FILE", args[0], true, true)
+			if err != nil {
+				return err
+			}
+
+			return port
+		},
+	},
+	"FILE-EXISTS?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("FILE-EXISTS?", args, 1); err != nil {
+				return err
+			}
+
+			path, err := expectString("FILE-EXISTS?", args[0])
+			if err != nil {
+				return err
+			}
+
+			_, statErr := os.Stat(path.Value)
+			return toBoolean(statErr == nil)
+		},
+	},
+	"FILE-DIRECTORY?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("FILE-DIRECTORY?", args, 1); err != nil {
+				return err
+			}
+
+			path, err := expectString("FILE-DIRECTORY?", args[0])
+			if err != nil {
+				return err
+			}
+
+			info, statErr := os.Stat(path.Value)
+			return toBoolean(statErr == nil && info.IsDir())
+		},
+	},
+	"DELETE-FILE": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("DELETE-FILE", args, 1); err != nil {
+				return err
+			}
+
+			path, err := expectString("DELETE-FILE", args[0])
+			if err != nil {
+				return err
+			}
+
+			if removeErr := os.Remove(path.Value); removeErr != nil {
+				return fileError("DELETE-FILE", removeErr)
+			}
+
+			return UNSPECIFIED
+		},
+	},
+	"RENAME-FILE": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("RENAME-FILE", args, 2); err != nil {
+				return err
+			}
+
+			from, err := expectString("RENAME-FILE", args[0])
+			if err != nil {
+				return err
+			}
+
+			to, err := expectString("RENAME-FILE", args[1])
+			if err != nil {
+				return err
+			}
+
+			if renameErr := os.Rename(from.Value, to.Value); renameErr != nil {
+				return fileError("RENAME-FILE", renameErr)
+			}
+
+			return UNSPECIFIED
+		},
+	},
+	"CREATE-DIRECTORY": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("CREATE-DIRECTORY", args, 1); err != nil {
+				return err
+			}
+
+			path, err := expectString("CREATE-DIRECTORY", args[0])
+			if err != nil {
+				return err
+			}
+
+			if mkdirErr := os.Mkdir(path.Value, 0777); mkdirErr != nil {
+				return fileError("CREATE-DIRECTORY", mkdirErr)
+			}
+
+			return UNSPECIFIED
+		},
+	},
+	"DIRECTORY-FILES": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("DIRECTORY-FILES", args, 1); err != nil {
+				return err
+			}
+
+			path, err := expectString("DIRECTORY-FILES", args[0])
+			if err != nil {
+				return err
+			}
+
+			entries, readErr := os.ReadDir(path.Value)
+			if readErr != nil {
+				return fileError("DIRECTORY-FILES", readErr)
+			}
+
+			names := []Object{}
+			for _, entry := range entries {
+				names = append(names, &String{Value: entry.Name()})
+			}
+
+			return sliceToList(names, NIL)
+		},
+	},
+	"COMMAND-LINE": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("COMMAND-LINE", args, 0); err != nil {
+				return err
+			}
+
+			arguments := []Object{}
+			for _, argument := range commandLine {
+				arguments = append(arguments, &String{Value: argument})
+			}
+
+			return sliceToList(arguments, NIL)
+		},
+	},
+	"EMERGENCY-EXIT": &Builtin{
+		Fn: func(args ...Object) Object {
+			code, err := exitCode(args)
+			if err != nil {
+				return err
+			}
+
+			os.Exit(code)
+			return UNSPECIFIED
+		},
+	},
+	"HASH-TABLE?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("HASH-TABLE?", args, 1); err != nil {
+				return err
+			}
+
+			_, ok := args[0].(*HashTable)
+			return toBoolean(ok)
+		},
+	},
+	"HASH-TABLE-REF/DEFAULT": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("HASH-TABLE-REF/DEFAULT", args, 3); err != nil {
+				return err
+			}
+
+			table, err := expectHashTable("HASH-TABLE-REF/DEFAULT", args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := expectKey("HASH-TABLE-REF/DEFAULT", table, args[1]); err != nil {
+				return err
+			}
+
+			if entry := table.lookup(args[1]); entry != nil {
+				return entry.value
+			}
+
+			return args[2]
+		},
+	},
+	"HASH-TABLE-SET!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 || len(args)%2 == 0 {
+				return newError("HASH-TABLE-SET! expecting a hash table followed by keys and values")
+			}
+
+			table, err := expectHashTable("HASH-TABLE-SET!", args[0])
+			if err != nil {
+				return err
+			}
+
+			for idx := 1; idx < len(args); idx += 2 {
+				if err := expectKey("HASH-TABLE-SET!", table, args[idx]); err != nil {
+					return err
+				}
+
+				table.set(args[idx], args[idx+1])
+			}
+
+			return UNSPECIFIED
+		},
+	},
+	"HASH-TABLE-DELETE!": &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) == 0 {
+				return newError("HASH-TABLE-DELETE! expecting a hash table followed by keys")
+			}
+
+			table, err := expectHashTable("HASH-TABLE-DELETE!", args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, key := range args[1:] {
+				table.delete(key)
+			}
+
+			return UNSPECIFIED
+		},
+	},
+	"HASH-TABLE-KEYS": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("HASH-TABLE-KEYS", args, 1); err != nil {
+				return err
+			}
+
+			table, err := expectHashTable("HASH-TABLE-KEYS", args[0])
+			if err != nil {
+				return err
+			}
+
+			keys := []Object{}
+			for _, entry := range table.snapshot() {
+				keys = append(keys, entry.key)
+			}
+
+			return sliceToList(keys, NIL)
+		},
+	},
+	"HASH-TABLE->ALIST": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("HASH-TABLE->ALIST", args, 1); err != nil {
+				return err
+			}
+
+			table, err := expectHashTable("HASH-TABLE->ALIST", args[0])
+			if err != nil {
+				return err
+			}
+
+			alist := []Object{}
+			for _, entry := range table.snapshot() {
+				alist = append(alist, &Pair{Car: entry.key, Cdr: entry.value})
+			}
+
+			return sliceToList(alist, NIL)
+		},
+	},
+	"HASH-TABLE-COUNT": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("HASH-TABLE-COUNT", args, 1); err != nil {
+				return err
+			}
+
+			table, err := expectHashTable("HASH-TABLE-COUNT", args[0])
+			if err != nil {
+				return err
+			}
+
+			return &Integer{Value: int64(table.entries.Len())}
+		},
+	},
+	"ERROR-OBJECT?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("ERROR-OBJECT?", args, 1); err != nil {
+				return err
+			}
+
+			_, ok := args[0].(*ErrorObject)
+			return toBoolean(ok)
+		},
+	},
+	"ERROR-OBJECT-MESSAGE": &Builtin{
+		Fn: func(args ...Object) Object {
+			errorObj, err := expectErrorObject("ERROR-OBJECT-MESSAGE", args)
+			if err != nil {
+				return err
+			}
+
+			return &String{Value: errorObj.Message}
+		},
+	},
+	"ERROR-OBJECT-IRRITANTS": &Builtin{
+		Fn: func(args ...Object) Object {
+			errorObj, err := expectErrorObject("ERROR-OBJECT-IRRITANTS", args)
+			if err != nil {
+				return err
+			}
+
+			return errorObj.Irritants
+		},
+	},
+	"ERROR-OBJECT-BACKTRACE": &Builtin{
+		Fn: func(args ...Object) Object {
+			errorObj, err := expectErrorObject("ERROR-OBJECT-BACKTRACE", args)
+			if err != nil {
+				return err
+			}
+
+			frames := []Object{}
+			for _, frame := range errorObj.Backtrace {
+				frames = append(frames, &String{Value: frame.String()})
+			}
+
+			return sliceToList(frames, NIL)
+		},
+	},
+	"FILE-ERROR?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("FILE-ERROR?", args, 1); err != nil {
+				return err
+			}
+
+			errorObj, ok := args[0].(*ErrorObject)
+			return toBoolean(ok && errorObj.Kind == fileErrorKind)
+		},
+	},
+	"READ-ERROR?": &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs("READ-ERROR?", args, 1); err != nil {
+				return err
+			}
+
+			errorObj, ok := args[0].(*ErrorObject)
+			return toBoolean(ok && errorObj.Kind == readErrorKind)
+		},
+	},
+	"CALL-METHOD": &Builtin{
+		Fn: func(args ...Object) Object {
+			// methName := args[0].(*String)
+			// obj := args[1]
+			// // params := args[2:len(args)]
+			// values := reflect.ValueOf(obj).MethodByName(methName.Value).Call([]reflect.Value{})
+
+			// return &String{Value: values[0].String()}
+			if methodName, ok := args[0].(*String); ok {
+				obj := args[1]
+				values := reflect.ValueOf(obj).MethodByName(methodName.Value).Call([]reflect.Value{})
+				return &String{Value: values[0].String()}
+			}
+
+			return newError("Expecting a string as the first argument")
+		},
+	},
+	"CALL-FIELD": &Builtin{
+		Fn: func(args ...Object) Object {
+			if fieldName, ok := args[0].(*String); ok {
+				obj := args[1]
+
+				value := reflect.ValueOf(obj).FieldByName(fieldName.Value)
+				return &String{Value: value.String()}
+			}
+
+			return newError("Expecting a string as the first argument")
+		},
+	},
+}
+
+func loadScopedBuiltins() {
+	eval := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if len(args) == 2 {
+				if evalEnv, ok := args[1].(*Environment); ok {
+					return Eval(Expand(args[0], evalEnv), evalEnv)
+				}
+
+				return errorObject(fmt.Errorf("EVAL expecting an environment found %s", args[1].Inspect()))
+			}
+
+			if err := expectArgs("EVAL", args, 1); err != nil {
+				return err
+			}
+
+			return Eval(Expand(args[0], env), env)
+		},
+	}
+
+	env := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			return env
+		},
+	}
+
+	callWithValues := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("CALL-WITH-VALUES", args, 2); err != nil {
+				return err
+			}
+
+			values := applyProcedure(args[0], "CALL-WITH-VALUES", []Object{}, env)
+			if isError(values) {
+				return values
+			}
+
+			if multiple, ok := values.(*Values); ok {
+				return applyProcedure(args[1], "CALL-WITH-VALUES", multiple.Value, env)
+			}
+
+			return applyProcedure(args[1], "CALL-WITH-VALUES", []Object{values}, env)
+		},
+	}
+
+	callCC := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("CALL/CC", args, 1); err != nil {
+				return err
+			}
+
+			return callWithContinuation(args[0], env)
+		},
+	}
+
+	dynamicWindBuiltin := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("DYNAMIC-WIND", args, 3); err != nil {
+				return err
+			}
+
+			return dynamicWind(args[0], args[1], args[2], env)
+		},
+	}
+
+	errorBuiltin := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if len(args) == 0 {
+				return newError("ERROR expecting at least 1 argument")
+			}
+
+			message, ok := args[0].(*String)
+			if !ok {
+				return errorObject(fmt.Errorf("ERROR expecting a string message found %s", args[0].Inspect()))
+			}
+
+			return signal(conditionError("", message.Value, args[1:]), env)
+		},
+	}
+
+	raise := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("RAISE", args, 1); err != nil {
+				return err
+			}
+
+			return signal(raiseError(args[0], false), env)
+		},
+	}
+
+	raiseContinuable := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("RAISE-CONTINUABLE", args, 1); err != nil {
+				return err
+			}
+
+			return signal(raiseError(args[0], true), env)
+		},
+	}
+
+	withExceptionHandlerBuiltin := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("WITH-EXCEPTION-HANDLER", args, 2); err != nil {
+				return err
+			}
+
+			return withExceptionHandler(args[0], args[1], env)
+		},
+	}
+
+	makeHashTable := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if len(args) == 0 {
+				return newHashTable(comparators[0])
+			}
+
+			if err := expectArgs("MAKE-HASH-TABLE", args, 1); err != nil {
+				return err
+			}
+
+			for _, comparator := range comparators {
+				if builtin, ok := builtins[comparator.name]; ok && Object(builtin) == args[0] {
+					return newHashTable(comparator)
+				}
+			}
+
+			return errorObject(fmt.Errorf("MAKE-HASH-TABLE expecting one of equal? eqv? eq? or string=? found %s", args[0].Inspect()))
+		},
+	}
+
+	hashTableRefBuiltin := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			return hashTableRef("HASH-TABLE-REF", args, env)
+		},
+	}
+
+	hashTableUpdate := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if len(args) < 3 || len(args) > 5 {
+				return errorObject(fmt.Errorf("HASH-TABLE-UPDATE! expecting 3 to 5 arguments got %d", len(args)))
+			}
+
+			current := hashTableRef("HASH-TABLE-UPDATE!", append([]Object{args[0], args[1]}, args[3:]...), env)
+			if isError(current) {
+				return current
+			}
+
+			updated := applyProcedure(args[2], "HASH-TABLE-UPDATE!", []Object{current}, env)
+			if isError(updated) {
+				return updated
+			}
+
+			args[0].(*HashTable).set(args[1], updated)
+			return UNSPECIFIED
+		},
+	}
+
+	hashTableWalk := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("HASH-TABLE-WALK", args, 2); err != nil {
+				return err
+			}
+
+			table, err := expectHashTable("HASH-TABLE-WALK", args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range table.snapshot() {
+				if result := applyProcedure(args[1], "HASH-TABLE-WALK", []Object{entry.key, entry.value}, env); isError(result) {
+					return result
+				}
+			}
+
+			return UNSPECIFIED
+		},
+	}
+
+	vectorMap := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if len(args) < 2 {
+				return newError("VECTOR-MAP expecting (VECTOR-MAP proc vector...)")
+			}
+
+			vectors, length, err := vectorArguments("VECTOR-MAP", args[1:])
+			if err != nil {
+				return err
+			}
+
+			items := make([]Object, length)
+			for idx := range items {
+				itemArgs := []Object{}
+				for _, vector := range vectors {
+					itemArgs = append(itemArgs, vector.Value[idx])
+				}
+
+				items[idx] = applyProcedure(args[0], "VECTOR-MAP", itemArgs, env)
+				if isError(items[idx]) {
+					return items[idx]
+				}
+			}
+
+			return &Vector{Value: items}
+		},
+	}
+
+	vectorForEach := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if len(args) < 2 {
+				return newError("VECTOR-FOR-EACH expecting (VECTOR-FOR-EACH proc vector...)")
+			}
+
+			vectors, length, err := vectorArguments("VECTOR-FOR-EACH", args[1:])
+			if err != nil {
+				return err
+			}
+
+			for idx := 0; idx < length; idx++ {
+				itemArgs := []Object{}
+				for _, vector := range vectors {
+					itemArgs = append(itemArgs, vector.Value[idx])
+				}
+
+				if result := applyProcedure(args[0], "VECTOR-FOR-EACH", itemArgs, env); isError(result) {
+					return result
+				}
+			}
+
+			return UNSPECIFIED
+		},
+	}
+
+	withOutputToString := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("WITH-OUTPUT-TO-STRING", args, 1); err != nil {
+				return err
+			}
+
+			port := newOutputString()
+			if result := withCurrentPort(&currentOutput, port, args[0], "WITH-OUTPUT-TO-STRING", env); isError(result) {
+				return result
+			}
+
+			return port.outputString("WITH-OUTPUT-TO-STRING")
+		},
+	}
+
+	callWithOutputString := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("CALL-WITH-OUTPUT-STRING", args, 1); err != nil {
+				return err
+			}
+
+			port := newOutputString()
+			if result := applyProcedure(args[0], "CALL-WITH-OUTPUT-STRING", []Object{port}, env); isError(result) {
+				return result
+			}
+
+			return port.outputString("CALL-WITH-OUTPUT-STRING")
+		},
+	}
+
+	withInputFromString := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("WITH-INPUT-FROM-STRING", args, 2); err != nil {
+				return err
+			}
+
+			str, err := expectString("WITH-INPUT-FROM-STRING", args[0])
+			if err != nil {
+				return err
+			}
+
+			return withCurrentPort(&currentInput, newInputString(str.Value), args[1], "WITH-INPUT-FROM-STRING", env)
+		},
+	}
+
+	callWithPort := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("CALL-WITH-PORT", args, 2); err != nil {
+				return err
+			}
+
+			port, ok := args[0].(*Port)
+			if !ok {
+				return errorObject(fmt.Errorf("CALL-WITH-PORT expecting a port found %s", args[0].Inspect()))
+			}
+
+			result := applyProcedure(args[1], "CALL-WITH-PORT", []Object{port}, env)
+			if isError(result) {
+				return result
+			}
+
+			if err := port.close(); err != nil {
+				return err
+			}
+
+			return result
+		},
+	}
+
+	callWithInputFile := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("CALL-WITH-INPUT-FILE", args, 2); err != nil {
+				return err
+			}
+
+			return callWithFile("CALL-WITH-INPUT-FILE", args[0], false, env, func(port *Port) Object {
+				return applyProcedure(args[1], "CALL-WITH-INPUT-FILE", []Object{port}, env)
+			})
+		},
+	}
+
+	callWithOutputFile := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("CALL-WITH-OUTPUT-FILE", args, 2); err != nil {
+				return err
+			}
+
+			return callWithFile("CALL-WITH-OUTPUT-FILE", args[0], true, env, func(port *Port) Object {
+				return applyProcedure(args[1], "CALL-WITH-OUTPUT-FILE", []Object{port}, env)
+			})
+		},
+	}
+
+	withInputFromFile := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("WITH-INPUT-FROM-FILE", args, 2); err != nil {
+				return err
+			}
+
+			return callWithFile("WITH-INPUT-FROM-FILE", args[0], false, env, func(port *Port) Object {
+				return withCurrentPort(&currentInput, port, args[1], "WITH-INPUT-FROM-FILE", env)
+			})
+		},
+	}
+
+	withOutputToFile := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if err := expectArgs("WITH-OUTPUT-TO-FILE", args, 2); err != nil {
+				return err
+			}
+
+			return callWithFile("WITH-OUTPUT-TO-FILE", args[0], true, env, func(port *Port) Object {
+				return withCurrentPort(&currentOutput, port, args[1], "WITH-OUTPUT-TO-FILE", env)
+			})
+		},
+	}
+
+	load := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			if len(args) == 2 {
+				loadEnv, ok := args[1].(*Environment)
+				if !ok {
+					return errorObject(fmt.Errorf("LOAD expecting an environment found %s", args[1].Inspect()))
+				}
+
+				env = loadEnv
+			} else if err := expectArgs("LOAD", args, 1); err != nil {
+				return err
+			}
+
+			path, err := expectString("LOAD", args[0])
+			if err != nil {
+				return err
+			}
+
+			return LoadFile(path.Value, env)
+		},
+	}
+
+	exitBuiltin := &ScopedBuiltin{
+		Fn: func(env *Environment, args ...Object) Object {
+			code, err := exitCode(args)
+			if err != nil {
+				return err
+			}
+
+			return exit(code, env)
+		},
+	}
+
+	scopedBuiltins["EVAL"] = eval
+	scopedBuiltins["ENV"] = env
+	scopedBuiltins["CALL-WITH-VALUES"] = callWithValues
+	scopedBuiltins["CALL/CC"] = callCC
+	scopedBuiltins["CALL-WITH-CURRENT-CONTINUATION"] = callCC
+	scopedBuiltins["DYNAMIC-WIND"] = dynamicWindBuiltin
+	scopedBuiltins["ERROR"] = errorBuiltin
+	scopedBuiltins["RAISE"] = raise
+	scopedBuiltins["RAISE-CONTINUABLE"] = raiseContinuable
+	scopedBuiltins["WITH-EXCEPTION-HANDLER"] = withExceptionHandlerBuiltin
+	scopedBuiltins["MAKE-HASH-TABLE"] = makeHashTable
+	scopedBuiltins["HASH-TABLE-REF"] = hashTableRefBuiltin
+	scopedBuiltins["HASH-TABLE-UPDATE!"] = hashTableUpdate
+	scopedBuiltins["HASH-TABLE-WALK"] = hashTableWalk
+	scopedBuiltins["VECTOR-MAP"] = vectorMap
+	scopedBuiltins["VECTOR-FOR-EACH"] = vectorForEach
+	scopedBuiltins["WITH-OUTPUT-TO-STRING"] = withOutputToString
+	scopedBuiltins["CALL-WITH-OUTPUT-STRING"] = callWithOutputString
+	scopedBuiltins["WITH-INPUT-FROM-STRING"] = withInputFromString
+	scopedBuiltins["CALL-WITH-PORT"] = callWithPort
+	scopedBuiltins["CALL-WITH-INPUT-FILE"] = callWithInputFile
+	scopedBuiltins["CALL-WITH-OUTPUT-FILE"] = callWithOutputFile
+	scopedBuiltins["WITH-INPUT-FROM-FILE"] = withInputFromFile
+	scopedBuiltins["WITH-OUTPUT-TO-FILE"] = withOutputToFile
+	scopedBuiltins["LOAD"] = load
+	scopedBuiltins["EXIT"] = exitBuiltin
+}
+
+func toBoolean(value bool) *Boolean {
+	if value {
+		return TRUE
+	}
+
+	return FALSE
+}
+
+// expectArgs checks the number of arguments passed to a builtin
+func expectArgs(name string, args []Object, count int) *Error {
+	if len(args) != count {
+		return errorObject(fmt.Errorf("%s expecting %d argument(s) got %d", name, count, len(args)))
+	}
+
+	return nil
+}
+
+// expectNumbers checks the argument count and that every argument is a number
+func expectNumbers(name string, args []Object, count int) *Error {
+	if err := expectArgs(name, args, count); err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		if !isNumber(arg) {
+			return notANumber(arg)
+		}
+	}
+
+	return nil
+}
+
+// expectPair checks a builtin was passed a single pair
+func expectPair(name string, args []Object) (*Pair, *Error) {
+	if err := expectArgs(name, args, 1); err != nil {
+		return nil, err
+	}
+
+	pair, ok := args[0].(*Pair)
+	if !ok {
+		return nil, errorObject(fmt.Errorf("%s expecting a pair found %s", name, args[0].Inspect()))
+	}
+
+	return pair, nil
+}
+
+// expectErrorObject checks for a single error object argument
+func expectErrorObject(name string, args []Object) (*ErrorObject, *Error) {
+	if err := expectArgs(name, args, 1); err != nil {
+		return nil, err
+	}
+
+	errorObj, ok := args[0].(*ErrorObject)
+	if !ok {
+		return nil, errorObject(fmt.Errorf("%s expecting an error object found %s", name, args[0].Inspect()))
+	}
+
+	return errorObj, nil
+}
+
+// expectVector checks obj is a vector
+func expectVector(name string, obj Object) (*Vector, *Error) {
+	vector, ok := obj.(*Vector)
+	if !ok {
+		return nil, errorObject(fmt.Errorf("%s expecting a vector found %s", name, obj.Inspect()))
+	}
+
+	return vector, nil
+}
+
+// expectMutableVector checks obj is a vector that is not a literal constant
+func expectMutableVector(name string, obj Object) (*Vector, *Error) {
+	vector, err := expectVector(name, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if vector.Constant {
+		return nil, errorObject(fmt.Errorf("%s cannot modify the constant vector %s", name, vector.Inspect()))
+	}
+
+	return vector, nil
+}
+
+// vectorArguments checks every argument is a vector returning the length of
+// the shortest
+func vectorArguments(name string, args []Object) ([]*Vector, int, *Error) {
+	vectors := []*Vector{}
+	length := -1
+
+	for _, arg := range args {
+		vector, err := expectVector(name, arg)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if length < 0 || len(vector.Value) < length {
+			length = len(vector.Value)
+		}
+
+		vectors = append(vectors, vector)
+	}
+
+	return vectors, length, nil
+}
+
+// expectRadix reads an optional radix argument of 2, 8, 10 or 16
+func expectRadix(name string, args []Object) (int, *Error) {
+	if len(args) == 0 {
+		return 10, nil
+	}
+
+	radix, ok := args[0].(*Integer)
+	if !ok || (radix.Value != 2 && radix.Value != 8 && radix.Value != 10 && radix.Value != 16) {
+		return 0, errorObject(fmt.Errorf("%s expecting a radix of 2, 8, 10 or 16 found %s", name, args[0].Inspect()))
+	}
+
+	return int(radix.Value), nil
+}
+
+// expectIndex checks obj is a non-negative Integer
+func expectIndex(name string, obj Object) (int, *Error) {
+	index, ok := obj.(*Integer)
+	if !ok || index.Value < 0 {
+		return 0, errorObject(fmt.Errorf("%s expecting a non-negative index found %s", name, obj.Inspect()))
+	}
+
+	return int(index.Value), nil
+}
+
+// isList is true for proper lists, it stops on circular lists
+func isList(obj Object) bool {
+	slow := obj
+
+	for {
+		if obj == NIL {
+			return true
+		}
+
+		pair, ok := obj.(*Pair)
+		if !ok {
+			return false
+		}
+
+		obj = pair.Cdr
+		if obj == NIL {
+			return true
+		}
+
+		pair, ok = obj.(*Pair)
+		if !ok {
+			return false
+		}
+
+		obj = pair.Cdr
+		slow = slow.(*Pair).Cdr
+		if obj == slow {
+			return false
+		}
+	}
+}
+
+func listTail(name string, list Object, k Object) Object {
+	index, err := expectIndex(name, k)
+	if err != nil {
+		return err
+	}
+
+	for ; index > 0; index-- {
+		pair, ok := list.(*Pair)
+		if !ok {
+			return errorObject(fmt.Errorf("%s index %s out of range", name, k.Inspect()))
+		}
+
+		list = pair.Cdr
+	}
+
+	return list
 }
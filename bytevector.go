@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// readBytevector reads the rest of a #u8( literal
+func (r *Reader) readBytevector() Object {
+	prefix, err := r.reader.Peek(3)
+	if err != nil || (prefix[0] != 'u' && prefix[0] != 'U') || prefix[1] != '8' || prefix[2] != '(' {
+		return readError("Expecting #u8( to start a bytevector")
+	}
+
+	for range prefix {
+		r.skip()
+	}
+
+	values := []byte{}
+	for {
+		peekChar, err := r.peek()
+		if err != nil {
+			return err
+		}
+
+		if peekChar == ')' {
+			r.skip()
+			break
+		}
+
+		obj := r.Read()
+		if isError(obj) {
+			return obj
+		}
+
+		value, ok := obj.(*Integer)
+		if !ok || value.Value < 0 || value.Value > 255 {
+			return readError(fmt.Sprintf("Bytevector expecting a byte found %s", obj.Inspect()))
+		}
+
+		values = append(values, byte(value.Value))
+	}
+
+	return &Bytevector{Value: values, Constant: true}
+}
+
+// expectBytevector checks obj is a bytevector
+func expectBytevector(name string, obj Object) (*Bytevector, *Error) {
+	bytevector, ok := obj.(*Bytevector)
+	if !ok {
+		return nil, errorObject(fmt.Errorf("%s expecting a bytevector found %s", name, obj.Inspect()))
+	}
+
+	return bytevector, nil
+}
+
+// expectMutableBytevector checks obj is a bytevector that is not a literal
+// constant
+func expectMutableBytevector(name string, obj Object) (*Bytevector, *Error) {
+	bytevector, err := expectBytevector(name, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytevector.Constant {
+		return nil, errorObject(fmt.Errorf("%s cannot modify the constant bytevector %s", name, bytevector.Inspect()))
+	}
+
+	return bytevector, nil
+}
+
+// expectByte checks obj is an exact integer from 0 to 255
+func expectByte(name string, obj Object) (byte, *Error) {
+	value, ok := obj.(*Integer)
+	if !ok || value.Value < 0 || value.Value > 255 {
+		return 0, errorObject(fmt.Errorf("%s expecting a byte found %s", name, obj.Inspect()))
+	}
+
+	return byte(value.Value), nil
+}
+
+// expectEndianness reads an optional BIG or LITTLE symbol defaulting to big
+// endian
+func expectEndianness(name string, args []Object) (binary.ByteOrder, *Error) {
+	if len(args) == 0 {
+		return binary.BigEndian, nil
+	}
+
+	if len(args) == 1 {
+		if isSymbol(args[0], "BIG") {
+			return binary.BigEndian, nil
+		}
+
+		if isSymbol(args[0], "LITTLE") {
+			return binary.LittleEndian, nil
+		}
+	}
+
+	return nil, errorObject(fmt.Errorf("%s expecting an endianness of big or little", name))
+}
+
+// expectSlot checks the size bytes from index are inside bytevector
+func expectSlot(name string, bytevector *Bytevector, obj Object, size int) ([]byte, *Error) {
+	index, err := expectIndex(name, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if index > len(bytevector.Value)-size {
+		return nil, errorObject(fmt.Errorf("%s index %d out of range for length %d", name, index, len(bytevector.Value)))
+	}
+
+	return bytevector.Value[index : index+size], nil
+}
+
+// readUint decodes an unsigned integer of size bytes
+func readUint(order binary.ByteOrder, slot []byte) uint64 {
+	switch len(slot) {
+	case 1:
+		return uint64(slot[0])
+	case 2:
+		return uint64(order.Uint16(slot))
+	case 4:
+		return uint64(order.Uint32(slot))
+	}
+
+	return order.Uint64(slot)
+}
+
+// writeUint encodes an unsigned integer of size bytes
+func writeUint(order binary.ByteOrder, slot []byte, value uint64) {
+	switch len(slot) {
+	case 1:
+		slot[0] = byte(value)
+	case 2:
+		order.PutUint16(slot, uint16(value))
+	case 4:
+		order.PutUint32(slot, uint32(value))
+	default:
+		order.PutUint64(slot, value)
+	}
+}
+
+// bytevectorIntRef creates a procedure decoding an integer of size bytes
+// such as BYTEVECTOR-U32-REF
+func bytevectorIntRef(name string, size int, signed bool) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 2 || len(args) > 3 {
+				return errorObject(fmt.Errorf("%s expecting 2 or 3 arguments got %d", name, len(args)))
+			}
+
+			bytevector, err := expectBytevector(name, args[0])
+			if err != nil {
+				return err
+			}
+
+			slot, err := expectSlot(name, bytevector, args[1], size)
+			if err != nil {
+				return err
+			}
+
+			order, err := expectEndianness(name, args[2:])
+			if err != nil {
+				return err
+			}
+
+			value := readUint(order, slot)
+			if signed {
+				shift := uint(64 - 8*size)
+				return &Integer{Value: int64(value<<shift) >> shift}
+			}
+
+			return normalizeBig(new(big.Int).SetUint64(value))
+		},
+	}
+}
+
+// bytevectorIntSet creates a procedure encoding an integer of size bytes
+// such as BYTEVECTOR-U32-SET!
+func bytevectorIntSet(name string, size int, signed bool) *Builtin {
+	low, high := big.NewInt(0), new(big.Int).Lsh(big.NewInt(1), uint(8*size))
+	if signed {
+		high.Rsh(high, 1)
+		low.Neg(high)
+	}
+
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 3 || len(args) > 4 {
+				return errorObject(fmt.Errorf("%s expecting 3 or 4 arguments got %d", name, len(args)))
+			}
+
+			bytevector, err := expectMutableBytevector(name, args[0])
+			if err != nil {
+				return err
+			}
+
+			slot, err := expectSlot(name, bytevector, args[1], size)
+			if err != nil {
+				return err
+			}
+
+			order, err := expectEndianness(name, args[3:])
+			if err != nil {
+				return err
+			}
+
+			if !isExactInteger(args[2]) {
+				return errorObject(fmt.Errorf("%s expecting an exact integer found %s", name, args[2].Inspect()))
+			}
+
+			value := toBig(args[2])
+			if value.Cmp(low) < 0 || value.Cmp(high) >= 0 {
+				return errorObject(fmt.Errorf("%s value %s out of range for %d bytes", name, args[2].Inspect(), size))
+			}
+
+			if value.Sign() < 0 {
+				value = new(big.Int).Add(value, new(big.Int).Lsh(big.NewInt(1), uint(8*size)))
+			}
+
+			writeUint(order, slot, value.Uint64())
+			return UNSPECIFIED
+		},
+	}
+}
+
+// bytevectorFloatRef creates a procedure decoding an IEEE float of size
+// bytes such as BYTEVECTOR-IEEE-DOUBLE-REF
+func bytevectorFloatRef(name string, size int) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 2 || len(args) > 3 {
+				return errorObject(fmt.Errorf("%s expecting 2 or 3 arguments got %d", name, len(args)))
+			}
+
+			bytevector, err := expectBytevector(name, args[0])
+			if err != nil {
+				return err
+			}
+
+			slot, err := expectSlot(name, bytevector, args[1], size)
+			if err != nil {
+				return err
+			}
+
+			order, err := expectEndianness(name, args[2:])
+			if err != nil {
+				return err
+			}
+
+			if size == 4 {
+				return &Float{Value: float64(math.Float32frombits(order.Uint32(slot)))}
+			}
+
+			return &Float{Value: math.Float64frombits(order.Uint64(slot))}
+		},
+	}
+}
+
+// bytevectorFloatSet creates a procedure encoding an IEEE float of size
+// bytes such as BYTEVECTOR-IEEE-DOUBLE-SET!
+func bytevectorFloatSet(name string, size int) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if len(args) < 3 || len(args) > 4 {
+				return errorObject(fmt.Errorf("%s expecting 3 or 4 arguments got %d", name, len(args)))
+			}
+
+			bytevector, err := expectMutableBytevector(name, args[0])
+			if err != nil {
+				return err
+			}
+
+			slot, err := expectSlot(name, bytevector, args[1], size)
+			if err != nil {
+				return err
+			}
+
+			order, err := expectEndianness(name, args[3:])
+			if err != nil {
+				return err
+			}
+
+			if !isNumber(args[2]) {
+				return notANumber(args[2])
+			}
+
+			if size == 4 {
+				order.PutUint32(slot, math.Float32bits(float32(toFloat(args[2]))))
+			} else {
+				order.PutUint64(slot, math.Float64bits(toFloat(args[2])))
+			}
+
+			return UNSPECIFIED
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+// wind is an entry of the DYNAMIC-WIND stack
+type wind struct {
+	before Object
+	after  Object
+	outer  *wind
+}
+
+// winds is the innermost active DYNAMIC-WIND
+var winds *wind
+
+// escape is panicked with to unwind the Go stack back to a CALL/CC
+type escape struct {
+	continuation *Continuation
+	value        Object
+}
+
+// callWithContinuation calls proc with a continuation that escapes back to
+// this call, the continuation can no longer be used once this call returns
+func callWithContinuation(proc Object, env *Environment) (result Object) {
+	k := &Continuation{winds: winds, handlers: handlers, active: true}
+
+	defer func() {
+		k.active = false
+
+		if r := recover(); r != nil {
+			if esc, ok := r.(*escape); ok && esc.continuation == k {
+				result = esc.value
+				return
+			}
+
+			panic(r)
+		}
+	}()
+
+	return applyProcedure(proc, "CALL/CC", []Object{k}, env)
+}
+
+// throw passes args to the CALL/CC that created k running the after thunks
+// of every DYNAMIC-WIND being left and reinstalling its exception handlers
+func throw(k *Continuation, args []Object, env *Environment) Object {
+	if !k.active {
+		return newError("Continuation is no longer active, only escaping continuations are supported")
+	}
+
+	for winds != nil && winds != k.winds {
+		after := winds.after
+		winds = winds.outer
+
+		if result := applyProcedure(after, "DYNAMIC-WIND", []Object{}, env); isError(result) {
+			return result
+		}
+	}
+
+	handlers = k.handlers
+
+	var value Object = &Values{Value: args}
+	if len(args) == 1 {
+		value = args[0]
+	}
+
+	panic(&escape{continuation: k, value: value})
+}
+
+// dynamicWind calls thunk between before and after, after also runs when a
+// continuation escapes from thunk
+func dynamicWind(before, thunk, after Object, env *Environment) Object {
+	if result := applyProcedure(before, "DYNAMIC-WIND", []Object{}, env); isError(result) {
+		return result
+	}
+
+	winds = &wind{before: before, after: after, outer: winds}
+	result := signalFresh(applyProcedure(thunk, "DYNAMIC-WIND", []Object{}, env), env)
+	winds = winds.outer
+
+	if afterResult := applyProcedure(after, "DYNAMIC-WIND", []Object{}, env); isError(afterResult) {
+		return afterResult
+	}
+
+	return result
+}
@@ -28,11 +28,24 @@ func (e *Environment) Get(name string) (Object, bool) {
 	return obj, ok
 }
 
+// Set defines name in this frame
 func (e *Environment) Set(name string, val Object) Object {
 	e.store[name] = val
 	return val
 }
 
+// Assign updates an existing binding in the nearest frame defining name
+func (e *Environment) Assign(name string, val Object) bool {
+	for env := e; env != nil; env = env.outer {
+		if _, ok := env.store[name]; ok {
+			env.store[name] = val
+			return true
+		}
+	}
+
+	return false
+}
+
 func (e *Environment) Inspect() string {
 	return fmt.Sprintf("%#v", e.store)
 }
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kinds of error objects told apart by FILE-ERROR? and READ-ERROR?
+const (
+	fileErrorKind = "FILE"
+	readErrorKind = "READ"
+)
+
+// handler is an entry of the exception handler stack, a guard entry catches
+// the exception for its GUARD form instead of calling a procedure
+type handler struct {
+	procedure Object
+	guard     bool
+	outer     *handler
+}
+
+// handlers is the innermost installed exception handler
+var handlers *handler
+
+// conditionError creates an error whose condition is an error object
+func conditionError(kind string, message string, irritants []Object) *Error {
+	msg := message
+	if len(irritants) > 0 {
+		inspected := []string{}
+		for _, irritant := range irritants {
+			inspected = append(inspected, irritant.Inspect())
+		}
+
+		msg = message + " " + strings.Join(inspected, " ")
+	}
+
+	frames := backtrace()
+
+	return &Error{
+		Value:     errors.New(msg),
+		Condition: &ErrorObject{Kind: kind, Message: message, Irritants: sliceToList(irritants, NIL), Backtrace: frames},
+		Backtrace: frames,
+	}
+}
+
+// readError creates an error satisfying READ-ERROR?
+func readError(msg string) *Error {
+	return conditionError(readErrorKind, msg, nil)
+}
+
+// raiseError wraps an object passed to RAISE, error objects keep their message
+func raiseError(obj Object, continuable bool) *Error {
+	err := &Error{Condition: obj, Backtrace: backtrace(), continuable: continuable}
+
+	if errorObj, ok := obj.(*ErrorObject); ok {
+		err.Value = errors.New(errorObj.Message)
+	} else {
+		err.Value = fmt.Errorf("Uncaught exception %s", obj.Inspect())
+	}
+
+	return err
+}
+
+// condition returns the object handed to handlers, internal errors become
+// error objects carrying their message
+func (e *Error) condition() Object {
+	if e.Condition == nil {
+		e.Condition = &ErrorObject{Message: e.Value.Error(), Irritants: NIL, Backtrace: e.Backtrace}
+	}
+
+	return e.Condition
+}
+
+// fresh checks the error has not been handed to any handler yet
+func (e *Error) fresh() bool {
+	return !e.delivered && e.target == nil
+}
+
+// signal hands err to the current handler, a handler returning from a
+// continuable exception gives the result while a non-continuable one raises
+// a secondary error in the outer handlers
+func signal(err *Error, env *Environment) Object {
+	frame := handlers
+	if frame == nil {
+		err.delivered = true
+		return err
+	}
+
+	if frame.guard {
+		err.target = frame
+		return err
+	}
+
+	err.delivered = true
+	handlers = frame.outer
+
+	result := applyProcedure(frame.procedure, "RAISE", []Object{err.condition()}, env)
+	if resultErr, ok := result.(*Error); ok {
+		if resultErr.fresh() {
+			result = signal(resultErr, env)
+		}
+	} else if !err.continuable {
+		result = signal(newError(fmt.Sprintf("Exception handler returned from non-continuable exception: %s", err.Value.Error())), env)
+	}
+
+	handlers = frame
+	return result
+}
+
+// signalFresh hands an error result nobody raised yet to the current
+// handler, it is called before any dynamic state is unwound so internal
+// errors reach the handler in the dynamic environment they occurred in
+func signalFresh(result Object, env *Environment) Object {
+	if err, ok := result.(*Error); ok && err.fresh() {
+		return signal(err, env)
+	}
+
+	return result
+}
+
+// withExceptionHandler calls thunk with handler installed, errors the thunk
+// returns without having been raised are handed to handler as well
+func withExceptionHandler(procedure, thunk Object, env *Environment) Object {
+	frame := &handler{procedure: procedure, outer: handlers}
+	handlers = frame
+
+	result := signalFresh(applyProcedure(thunk, "WITH-EXCEPTION-HANDLER", []Object{}, env), env)
+
+	handlers = frame.outer
+	return result
+}
+
+// evalGuard evaluates the body catching any exception raised in it, the
+// exception is bound to var for the COND like clauses and raised again when
+// no clause matches
+func evalGuard(node *Pair, env *Environment) (Object, *Environment) {
+	malformed := newError("Malformed GUARD expecting (GUARD (var clause...) body...)")
+
+	rest, ok := node.Cdr.(*Pair)
+	if !ok {
+		return malformed, nil
+	}
+
+	spec, ok := rest.Car.(*Pair)
+	if !ok {
+		return malformed, nil
+	}
+
+	variable, ok := spec.Car.(*Identifier)
+	if !ok {
+		return malformed, nil
+	}
+
+	clauses, listErr := listToSlice(spec.Cdr)
+	if listErr != nil {
+		return listErr, nil
+	}
+
+	frame := &handler{guard: true, outer: handlers}
+	handlers = frame
+
+	bodyEnv := NewEnclosedEnvironment(env)
+	result, tailEnv := evalSequence(rest.Cdr, bodyEnv)
+	if tailEnv != nil {
+		result = Eval(result, tailEnv)
+	}
+
+	handlers = frame.outer
+
+	err, ok := result.(*Error)
+	if !ok || !(err.target == frame || err.fresh()) {
+		return result, nil
+	}
+
+	err.target = nil
+	clauseEnv := NewEnclosedEnvironment(env)
+	clauseEnv.Set(variable.Value, err.condition())
+
+	if last, ok := lastClause(clauses); !ok || !isSymbol(last.Car, "ELSE") {
+		reraise := &ScopedBuiltin{
+			Fn: func(env *Environment, args ...Object) Object {
+				return signal(err, env)
+			},
+		}
+
+		clauses = append(clauses, &Pair{Car: &Identifier{Value: "ELSE"}, Cdr: &Pair{Car: &Pair{Car: reraise, Cdr: NIL}, Cdr: NIL}})
+	}
+
+	return evalCond(&Pair{Car: &Identifier{Value: "COND"}, Cdr: sliceToList(clauses, NIL)}, clauseEnv)
+}
+
+// lastClause returns the final GUARD clause
+func lastClause(clauses []Object) (*Pair, bool) {
+	if len(clauses) == 0 {
+		return nil, false
+	}
+
+	clause, ok := clauses[len(clauses)-1].(*Pair)
+	return clause, ok
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// outputFiles holds the open output file ports, their buffered output is
+// flushed when the program ends
+var outputFiles = map[*Port]bool{}
+
+// flushFiles flushes every open output file port
+func flushFiles() {
+	for port := range outputFiles {
+		port.flush()
+	}
+}
+
+// fileError creates an error satisfying FILE-ERROR? from a failed file
+// system operation, the file names become the irritants
+func fileError(name string, err error) *Error {
+	switch failure := err.(type) {
+	case *os.PathError:
+		return conditionError(fileErrorKind, fmt.Sprintf("%s %s", name, failure.Err), []Object{&String{Value: failure.Path}})
+	case *os.LinkError:
+		return conditionError(fileErrorKind, fmt.Sprintf("%s %s", name, failure.Err), []Object{&String{Value: failure.Old}, &String{Value: failure.New}})
+	}
+
+	return conditionError(fileErrorKind, fmt.Sprintf("%s %s", name, err), nil)
+}
+
+// openFile opens path for reading or, truncating it first, for writing
+func openFile(name string, path Object, output bool, binary bool) (*Port, *Error) {
+	str, err := expectString(name, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if output {
+		file, createErr := os.Create(str.Value)
+		if createErr != nil {
+			return nil, fileError(name, createErr)
+		}
+
+		port := newOutputPort(str.Value, bufio.NewWriter(file), file)
+		port.binary = binary
+		outputFiles[port] = true
+		return port, nil
+	}
+
+	file, openErr := os.Open(str.Value)
+	if openErr != nil {
+		return nil, fileError(name, openErr)
+	}
+
+	port := newInputPort(str.Value, NewBufferedReader(bufio.NewReader(file), str.Value), file)
+	port.binary = binary
+	return port, nil
+}
+
+// callWithFile opens path and hands the port to use, the port is closed
+// however use exits
+func callWithFile(name string, path Object, output bool, env *Environment, use func(port *Port) Object) Object {
+	port, err := openFile(name, path, output, false)
+	if err != nil {
+		return err
+	}
+
+	defer port.close()
+
+	result := signalFresh(use(port), env)
+	if isError(result) {
+		return result
+	}
+
+	if err := port.close(); err != nil {
+		return err
+	}
+
+	return result
+}
+
+// newInputBytevector creates a binary port reading a copy of value
+func newInputBytevector(value []byte) *Port {
+	reader := bufio.NewReader(bytes.NewReader(append([]byte{}, value...)))
+	port := newInputPort("bytevector", NewBufferedReader(reader, "bytevector"), nil)
+	port.binary = true
+	return port
+}
+
+// newOutputBytevector creates a binary port collecting what is written in
+// memory
+func newOutputBytevector() *Port {
+	port := newOutputPort("bytevector", &bytes.Buffer{}, nil)
+	port.binary = true
+	return port
+}
+
+// outputBytevector returns what was written to a bytevector port
+func (p *Port) outputBytevector(name string) Object {
+	buffer, ok := p.writer.(*bytes.Buffer)
+	if !ok {
+		return errorObject(fmt.Errorf("%s expecting an output bytevector port found %s", name, p.Inspect()))
+	}
+
+	return &Bytevector{Value: append([]byte{}, buffer.Bytes()...)}
+}
+
+// readByte reads a byte or returns the eof object
+func (p *Port) readByte() Object {
+	value, err := p.reader.currentByte()
+	if err != nil {
+		return p.eof(err)
+	}
+
+	return &Integer{Value: int64(value)}
+}
+
+// peekByte returns the next byte without consuming it
+func (p *Port) peekByte() Object {
+	peeked, err := p.reader.reader.Peek(1)
+	if len(peeked) == 0 {
+		if err == io.EOF {
+			return EOFOBJECT
+		}
+
+		return errorObject(err)
+	}
+
+	return &Integer{Value: int64(peeked[0])}
+}
+
+// readBytes reads up to count bytes, the buffer grows with what is read so a
+// huge count does not allocate up front
+func (p *Port) readBytes(count int) Object {
+	value, err := io.ReadAll(io.LimitReader(p.reader.reader, int64(count)))
+	if err != nil {
+		return errorObject(err)
+	}
+
+	if len(value) == 0 && count > 0 {
+		return EOFOBJECT
+	}
+
+	return &Bytevector{Value: value}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// hashDepth limits how far into pairs and vectors equal? hashing looks so
+// cyclic keys still hash
+const hashDepth = 4
+
+// comparator decides which keys of a HashTable are the same
+type comparator struct {
+	name  string
+	equal func(a, b Object) bool
+	hash  func(obj Object) string
+	key   func(obj Object) bool
+}
+
+var comparators = []*comparator{
+	{name: "EQUAL?", equal: isEqual, hash: equalHash},
+	{name: "EQV?", equal: isEqv, hash: eqvHash},
+	{name: "EQ?", equal: isEq, hash: eqvHash},
+	{name: "STRING=?", equal: isEqual, hash: equalHash, key: isString},
+}
+
+// hashEntry is a key value association of a HashTable
+type hashEntry struct {
+	key     Object
+	value   Object
+	element *list.Element
+}
+
+// newHashTable creates an empty table keyed by comparator
+func newHashTable(comparator *comparator) *HashTable {
+	return &HashTable{comparator: comparator, buckets: map[string][]*hashEntry{}, entries: list.New()}
+}
+
+// lookup finds the entry for key
+func (t *HashTable) lookup(key Object) *hashEntry {
+	for _, entry := range t.buckets[t.comparator.hash(key)] {
+		if t.comparator.equal(entry.key, key) {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// set associates value with key keeping the order keys were first added in
+func (t *HashTable) set(key, value Object) {
+	if entry := t.lookup(key); entry != nil {
+		entry.value = value
+		return
+	}
+
+	hash := t.comparator.hash(key)
+	entry := &hashEntry{key: key, value: value}
+	entry.element = t.entries.PushBack(entry)
+	t.buckets[hash] = append(t.buckets[hash], entry)
+}
+
+// delete removes the entry for key
+func (t *HashTable) delete(key Object) {
+	hash := t.comparator.hash(key)
+	bucket := t.buckets[hash]
+
+	for idx, entry := range bucket {
+		if t.comparator.equal(entry.key, key) {
+			t.entries.Remove(entry.element)
+			t.buckets[hash] = append(bucket[:idx], bucket[idx+1:]...)
+
+			if len(t.buckets[hash]) == 0 {
+				delete(t.buckets, hash)
+			}
+
+			return
+		}
+	}
+}
+
+// snapshot returns the entries so procedures may modify the table while
+// walking it
+func (t *HashTable) snapshot() []*hashEntry {
+	entries := make([]*hashEntry, 0, t.entries.Len())
+	for element := t.entries.Front(); element != nil; element = element.Next() {
+		entries = append(entries, element.Value.(*hashEntry))
+	}
+
+	return entries
+}
+
+// eqvHash hashes numbers by value and exactness, characters, booleans and
+// symbols by value and anything else by identity
+func eqvHash(obj Object) string {
+	switch node := obj.(type) {
+	case *Integer:
+		return "i" + strconv.FormatInt(node.Value, 10)
+	case *BigInteger:
+		return "i" + node.Value.String()
+	case *Rational:
+		return "r" + node.Value.RatString()
+	case *Float:
+		if node.Value == 0 {
+			return "f0"
+		}
+
+		return "f" + strconv.FormatFloat(node.Value, 'g', -1, 64)
+	case *Char:
+		return "c" + string(node.Value)
+	case *Boolean:
+		return "b" + strconv.FormatBool(node.Value)
+	case *Identifier:
+		return "y" + node.Value
+	}
+
+	return fmt.Sprintf("p%p", obj)
+}
+
+// equalHash hashes strings and bytevectors by content and pairs and vectors by their first
+// elements
+func equalHash(obj Object) string {
+	return structuralHash(obj, hashDepth)
+}
+
+func structuralHash(obj Object, depth int) string {
+	if depth == 0 {
+		return "..."
+	}
+
+	switch node := obj.(type) {
+	case *String:
+		return "s" + node.Value
+	case *Bytevector:
+		return "u8" + string(node.Value)
+	case *EmptyList:
+		return "()"
+	case *Pair:
+		strs := []string{}
+
+		var rest Object = node
+		for len(strs) < hashDepth {
+			pair, ok := rest.(*Pair)
+			if !ok {
+				break
+			}
+
+			strs = append(strs, structuralHash(pair.Car, depth-1))
+			rest = pair.Cdr
+		}
+
+		return "(" + strings.Join(strs, " ") + ")"
+	case *Vector:
+		strs := []string{}
+
+		for idx, item := range node.Value {
+			if idx == hashDepth {
+				break
+			}
+
+			strs = append(strs, structuralHash(item, depth-1))
+		}
+
+		return "#(" + strings.Join(strs, " ") + ")"
+	}
+
+	return eqvHash(obj)
+}
+
+// expectHashTable checks obj is a hash table
+func expectHashTable(name string, obj Object) (*HashTable, *Error) {
+	table, ok := obj.(*HashTable)
+	if !ok {
+		return nil, errorObject(fmt.Errorf("%s expecting a hash table found %s", name, obj.Inspect()))
+	}
+
+	return table, nil
+}
+
+// expectKey checks key can be used with the table's comparator
+func expectKey(name string, table *HashTable, key Object) *Error {
+	if table.comparator.key != nil && !table.comparator.key(key) {
+		return errorObject(fmt.Errorf("%s expecting a key suitable for %s found %s", name, table.comparator.name, key.Inspect()))
+	}
+
+	return nil
+}
+
+// hashTableRef calls failure when key is missing and success with the
+// value when given
+func hashTableRef(name string, args []Object, env *Environment) Object {
+	if len(args) < 2 || len(args) > 4 {
+		return errorObject(fmt.Errorf("%s expecting 2 to 4 arguments got %d", name, len(args)))
+	}
+
+	table, err := expectHashTable(name, args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := expectKey(name, table, args[1]); err != nil {
+		return err
+	}
+
+	entry := table.lookup(args[1])
+	if entry == nil {
+		if len(args) < 3 {
+			return conditionError("", name+" key not found", []Object{args[1]})
+		}
+
+		return applyProcedure(args[2], name, []Object{}, env)
+	}
+
+	if len(args) == 4 {
+		return applyProcedure(args[3], name, []Object{entry.value}, env)
+	}
+
+	return entry.value
+}
@@ -1,109 +1,681 @@
 package main
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 )
 
+// specialForm evaluates a syntactic form, when an environment is returned the
+// returned object is an expression in tail position still to be evaluated in it
+type specialForm func(node *Pair, env *Environment) (Object, *Environment)
+
+var specialForms = map[string]specialForm{}
+
 // Load setups the inital environment and returns it
 func Load() *Environment {
+	loadSpecialForms()
 	loadScopedBuiltins()
-	return NewEnvironment()
+	env := NewEnvironment()
+
+	for _, obj := range NewReader(prelude).ReadAll() {
+		Eval(Expand(obj, env), env)
+	}
+
+	return env
+}
+
+func loadSpecialForms() {
+	specialForms["QUOTE"] = evalQuote
+	specialForms["QUASIQUOTE"] = evalQuasiquote
+	specialForms["DEFINE"] = evalDefine
+	specialForms["SET!"] = evalSet
+	specialForms["LAMBDA"] = evalLambda
+	specialForms["CASE-LAMBDA"] = evalCaseLambda
+	specialForms["IF"] = evalIf
+	specialForms["COND"] = evalCond
+	specialForms["CASE"] = evalCase
+	specialForms["BEGIN"] = evalBegin
+	specialForms["GUARD"] = evalGuard
+	specialForms["DEFINE-RECORD-TYPE"] = evalDefineRecordType
 }
 
-// Eval an object
+// Eval an object, tail calls loop here instead of recursing
 func Eval(obj Object, env *Environment) Object {
-	switch node := obj.(type) {
-	case *Boolean, *Char, *String, *Error, *Integer, *Float, *Vector, *Data:
-		return obj
-	case *Lambda:
-		node.Env = env
-		return node
-	case *Identifier:
-		if val, ok := env.Get(node.Value); ok {
-			return val
-		}
+	depth, outer := len(callStack), formPos
+	defer func() { callStack, formPos = callStack[:depth], outer }()
 
-		return newError(fmt.Sprintf("Unkown identifier %s", node.Value))
-	case *Pair:
-		car := node.Car
-		switch carType := car.(type) {
-		case *Lambda:
-			if node.Cdr != nil {
-				args, err := evalArgs(node.Cdr.(*Pair), env)
-				if err != nil {
-					return err
+	for {
+		switch node := obj.(type) {
+		case *Boolean, *Char, *String, *Error, *Integer, *BigInteger, *Rational, *Float, *Vector, *Builtin, *ScopedBuiltin, *Lambda, *CaseLambda, *Continuation, *ErrorObject, *RecordType, *Record, *HashTable, *Bytevector, *Port, *EndOfFile, *Unspecified, *Values, *Environment:
+			return obj
+		case *EmptyList:
+			return newError("Missing procedure expression in ()")
+		case *Identifier:
+			if val, ok := env.Get(node.Value); ok {
+				if _, ok := val.(*Macro); ok {
+					return atomError(node.Value, fmt.Sprintf("Syntax %s used as a variable", node.Value))
 				}
 
-				if len(args) == len(carType.Parameters) {
-					return applyFunction(carType, "#<procedure>", args)
-				}
+				return val
+			}
 
-				return newError("arguments do not match")
+			if builtin, ok := builtins[node.Value]; ok {
+				return builtin
 			}
 
-			return applyFunction(carType, "#<procedure>", []Object{})
-		case *Identifier:
-			if builtin, ok := builtins[carType.Value]; ok {
-				args, err := evalArgs(node.Cdr.(*Pair), env)
-				if err != nil {
-					return err
+			if scopedBuiltin, ok := scopedBuiltins[node.Value]; ok {
+				return scopedBuiltin
+			}
+
+			return atomError(node.Value, fmt.Sprintf("Unkown identifier %s", node.Value))
+		case *Pair:
+			if node.Pos != nil {
+				formPos = node.Pos
+			}
+
+			name := "#<procedure>"
+			if ident, ok := node.Car.(*Identifier); ok {
+				if form, ok := specialForms[ident.Value]; ok {
+					result, tailEnv := form(node, env)
+					if tailEnv == nil {
+						return result
+					}
+
+					obj, env = result, tailEnv
+					continue
 				}
 
-				return builtin.Fn(args...)
+				name = originalName(ident.Value)
 			}
 
-			if scopedBuiltin, ok := scopedBuiltins[carType.Value]; ok {
-				if node.Cdr != nil {
-					args, err := evalArgs(node.Cdr.(*Pair), env)
-					if err != nil {
-						return err
+			proc := evalAt(node.Car, node.CarPos, env)
+			if isError(proc) {
+				if _, ok := node.Car.(*Identifier); ok {
+					if node.CarPos != nil {
+						formPos = node.CarPos
 					}
 
-					return scopedBuiltin.Fn(env, args...)
+					return atomError(name, fmt.Sprintf("Unkown proc %s", name))
 				}
 
-				return scopedBuiltin.Fn(env, []Object{}...)
+				return proc
+			}
+
+			args, err := evalArgs(node.Cdr, env)
+			if err != nil {
+				return err
+			}
+
+			callStack = append(callStack[:depth], callFrame{Name: procedureName(proc, name), Pos: node.Pos})
+
+			switch proc.(type) {
+			case *Lambda, *CaseLambda:
+			default:
+				return applyProcedure(proc, name, args, env)
+			}
+
+			lambda, err := selectLambda(proc, name, args)
+			if err != nil {
+				return err
+			}
+
+			// the body was read elsewhere, the call's position no longer applies
+			formPos = nil
+			env = extendFunctionEnv(lambda, name, args)
+			body, tailEnv := evalSequence(lambda.Body, env)
+			if tailEnv == nil {
+				return body
+			}
+
+			obj = body
+		case *Macro:
+			return newError(fmt.Sprintf("Syntax %s used as a variable", node.Name))
+		default:
+			return newError(fmt.Sprintf("Cannot evaluate %T", obj))
+		}
+	}
+}
+
+// evalQuote returns the datum unevaluated
+func evalQuote(node *Pair, env *Environment) (Object, *Environment) {
+	args, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err, nil
+	}
+
+	if len(args) != 1 {
+		return newError("Malformed QUOTE expecting (QUOTE datum)"), nil
+	}
+
+	return args[0], nil
+}
+
+// evalQuasiquote returns the template with unquoted expressions evaluated
+func evalQuasiquote(node *Pair, env *Environment) (Object, *Environment) {
+	args, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err, nil
+	}
+
+	if len(args) != 1 {
+		return newError("Malformed QUASIQUOTE expecting (QUASIQUOTE template)"), nil
+	}
+
+	return quasiquote(args[0], 1, env), nil
+}
+
+// quasiquote expands template at the given nesting depth, only unquotes at
+// depth 1 are evaluated
+func quasiquote(template Object, depth int, env *Environment) Object {
+	switch node := template.(type) {
+	case *Pair:
+		if operand, ok := unquoteOperand(node, "UNQUOTE"); ok {
+			if depth == 1 {
+				return Eval(operand, env)
+			}
+
+			return quasiquoteForm("UNQUOTE", operand, depth-1, env)
+		}
+
+		if operand, ok := unquoteOperand(node, "QUASIQUOTE"); ok {
+			return quasiquoteForm("QUASIQUOTE", operand, depth+1, env)
+		}
+
+		items := []Object{}
+		var obj Object = node
+
+		for {
+			pair, ok := obj.(*Pair)
+			if !ok || isUnquote(pair) {
+				break
 			}
 
-			// Check the ENV for a Lambda
-			if val, ok := env.Get(carType.Value); ok {
-				if lambda, ok := val.(*Lambda); ok {
-					var params []Object
+			if operand, ok := unquoteOperand(pair.Car, "UNQUOTE-SPLICING"); ok {
+				if depth == 1 {
+					spliced := Eval(operand, env)
+					if isError(spliced) {
+						return spliced
+					}
 
-					if node.Cdr != nil {
-						args, err := evalArgs(node.Cdr.(*Pair), env)
-						if err != nil {
-							return err
-						}
+					list, err := listToSlice(spliced)
+					if err != nil {
+						return errorObject(fmt.Errorf("UNQUOTE-SPLICING expecting a list found %s", spliced.Inspect()))
+					}
 
-						params = args
+					items = append(items, list...)
+				} else {
+					item := quasiquoteForm("UNQUOTE-SPLICING", operand, depth-1, env)
+					if isError(item) {
+						return item
 					}
 
-					return applyFunction(lambda, carType.Value, params)
+					items = append(items, item)
+				}
+			} else {
+				item := quasiquote(pair.Car, depth, env)
+				if isError(item) {
+					return item
 				}
+
+				items = append(items, item)
 			}
 
-			if carType.Value == "DEFINE" {
-				ident := node.Cdr.(*Pair).Car.(*Identifier)
-				value := node.Cdr.(*Pair).Cdr.(*Pair).Car
-				env.Set(ident.Value, value)
-				return nil
+			obj = pair.Cdr
+		}
+
+		tail := quasiquote(obj, depth, env)
+		if isError(tail) {
+			return tail
+		}
+
+		return sliceToList(items, tail)
+	case *Vector:
+		list := quasiquote(sliceToList(node.Value, NIL), depth, env)
+		if isError(list) {
+			return list
+		}
+
+		items, err := listToSlice(list)
+		if err != nil {
+			return err
+		}
+
+		return &Vector{Value: items}
+	}
+
+	return template
+}
+
+// quasiquoteForm rebuilds (name operand) expanding operand at depth
+func quasiquoteForm(name string, operand Object, depth int, env *Environment) Object {
+	expanded := quasiquote(operand, depth, env)
+	if isError(expanded) {
+		return expanded
+	}
+
+	return &Pair{Car: intern(name), Cdr: &Pair{Car: expanded, Cdr: NIL}}
+}
+
+// unquoteOperand returns x when obj is the form (name x)
+func unquoteOperand(obj Object, name string) (Object, bool) {
+	pair, ok := obj.(*Pair)
+	if !ok || !isSymbol(pair.Car, name) {
+		return nil, false
+	}
+
+	rest, ok := pair.Cdr.(*Pair)
+	if !ok || rest.Cdr != NIL {
+		return nil, false
+	}
+
+	return rest.Car, true
+}
+
+// isUnquote catches (a . ,b) where the tail of a list is an unquote form
+func isUnquote(pair *Pair) bool {
+	_, unquote := unquoteOperand(pair, "UNQUOTE")
+	_, quasi := unquoteOperand(pair, "QUASIQUOTE")
+	return unquote || quasi
+}
+
+// evalDefine binds the evaluated value to the identifier in this frame,
+// (DEFINE (name params...) body...) is shorthand for a LAMBDA
+func evalDefine(node *Pair, env *Environment) (Object, *Environment) {
+	args, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err, nil
+	}
+
+	if len(args) < 2 {
+		return newError("Malformed DEFINE expecting (DEFINE name value)"), nil
+	}
+
+	if signature, ok := args[0].(*Pair); ok {
+		ident, ok := signature.Car.(*Identifier)
+		if !ok {
+			return newError(fmt.Sprintf("DEFINE expecting an identifier found %s", signature.Car.Inspect())), nil
+		}
+
+		lambda, err := newLambda(signature.Cdr, node.Cdr.(*Pair).Cdr, env)
+		if err != nil {
+			return err, nil
+		}
+
+		lambda.Name = originalName(ident.Value)
+		env.Set(ident.Value, lambda)
+		return UNSPECIFIED, nil
+	}
+
+	ident, ok := args[0].(*Identifier)
+	if !ok {
+		return newError(fmt.Sprintf("DEFINE expecting an identifier found %s", args[0].Inspect())), nil
+	}
+
+	if len(args) != 2 {
+		return newError("Malformed DEFINE expecting (DEFINE name value)"), nil
+	}
+
+	value := Eval(args[1], env)
+	if isError(value) {
+		return value, nil
+	}
+
+	switch proc := value.(type) {
+	case *Lambda:
+		if proc.Name == "" {
+			proc.Name = originalName(ident.Value)
+		}
+	case *CaseLambda:
+		if proc.Name == "" {
+			proc.Name = originalName(ident.Value)
+		}
+	}
+
+	env.Set(ident.Value, value)
+	return UNSPECIFIED, nil
+}
+
+// evalSet assigns to an existing binding searching the enclosing frames
+func evalSet(node *Pair, env *Environment) (Object, *Environment) {
+	args, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err, nil
+	}
+
+	if len(args) != 2 {
+		return newError("Malformed SET! expecting (SET! name value)"), nil
+	}
+
+	ident, ok := args[0].(*Identifier)
+	if !ok {
+		return newError(fmt.Sprintf("SET! expecting an identifier found %s", args[0].Inspect())), nil
+	}
+
+	value := Eval(args[1], env)
+	if isError(value) {
+		return value, nil
+	}
+
+	if !env.Assign(ident.Value, value) {
+		return newError(fmt.Sprintf("SET! unbound variable %s", ident.Value)), nil
+	}
+
+	return UNSPECIFIED, nil
+}
+
+// evalLambda creates a closure over env
+func evalLambda(node *Pair, env *Environment) (Object, *Environment) {
+	if node.Cdr == NIL {
+		return newError("Malformed LAMBDA expecting (LAMBDA (params...) body...)"), nil
+	}
+
+	rest := node.Cdr.(*Pair)
+	lambda, err := newLambda(rest.Car, rest.Cdr, env)
+	if err != nil {
+		return err, nil
+	}
+
+	return lambda, nil
+}
+
+// evalCaseLambda creates a procedure dispatching on the number of arguments
+func evalCaseLambda(node *Pair, env *Environment) (Object, *Environment) {
+	clauses, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err, nil
+	}
+
+	caseLambda := &CaseLambda{}
+	for _, obj := range clauses {
+		clause, ok := obj.(*Pair)
+		if !ok {
+			return newError("Malformed CASE-LAMBDA clause expecting (params body...)"), nil
+		}
+
+		lambda, err := newLambda(clause.Car, clause.Cdr, env)
+		if err != nil {
+			return err, nil
+		}
+
+		caseLambda.Clauses = append(caseLambda.Clauses, lambda)
+	}
+
+	return caseLambda, nil
+}
+
+// evalIf evaluates only the branch selected by the test
+func evalIf(node *Pair, env *Environment) (Object, *Environment) {
+	args, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err, nil
+	}
+
+	if len(args) != 2 && len(args) != 3 {
+		return newError("Malformed IF expecting (IF test consequent [alternative])"), nil
+	}
+
+	test := node.Cdr.(*Pair)
+	result := evalAt(test.Car, test.CarPos, env)
+	if isError(result) {
+		return result, nil
+	}
+
+	consequent := test.Cdr.(*Pair)
+	if isTruthy(result) {
+		return evalTail(consequent, env)
+	}
+
+	if alternative, ok := consequent.Cdr.(*Pair); ok {
+		return evalTail(alternative, env)
+	}
+
+	return UNSPECIFIED, nil
+}
+
+// evalCond evaluates the body of the first clause whose test is true
+func evalCond(node *Pair, env *Environment) (Object, *Environment) {
+	clauses, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err, nil
+	}
+
+	for idx, obj := range clauses {
+		clause, ok := obj.(*Pair)
+		if !ok {
+			return newError("Malformed COND clause expecting (test expression...)"), nil
+		}
+
+		var test Object
+		if isSymbol(clause.Car, "ELSE") {
+			if idx != len(clauses)-1 {
+				return newError("COND ELSE clause must be the last clause"), nil
 			}
 
-			return newError(fmt.Sprintf("Unkown proc %s", carType.Value))
-		default:
-			return Eval(carType, env)
+			test = TRUE
+		} else {
+			test = Eval(clause.Car, env)
+			if isError(test) {
+				return test, nil
+			}
+
+			if !isTruthy(test) {
+				continue
+			}
+		}
+
+		if clause.Cdr == NIL {
+			return test, nil
+		}
+
+		return evalClauseBody(clause.Cdr, test, env)
+	}
+
+	return UNSPECIFIED, nil
+}
+
+// evalCase compares the key against each clause's datums using eqv?
+func evalCase(node *Pair, env *Environment) (Object, *Environment) {
+	args, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err, nil
+	}
+
+	if len(args) == 0 {
+		return newError("Malformed CASE expecting (CASE key clause...)"), nil
+	}
+
+	key := Eval(args[0], env)
+	if isError(key) {
+		return key, nil
+	}
+
+	clauses := args[1:]
+	for idx, obj := range clauses {
+		clause, ok := obj.(*Pair)
+		if !ok || clause.Cdr == NIL {
+			return newError("Malformed CASE clause expecting ((datum...) expression...)"), nil
+		}
+
+		if isSymbol(clause.Car, "ELSE") {
+			if idx != len(clauses)-1 {
+				return newError("CASE ELSE clause must be the last clause"), nil
+			}
+
+			return evalClauseBody(clause.Cdr, key, env)
+		}
+
+		datums, err := listToSlice(clause.Car)
+		if err != nil {
+			return err, nil
+		}
+
+		for _, datum := range datums {
+			if isEqv(key, datum) {
+				return evalClauseBody(clause.Cdr, key, env)
+			}
+		}
+	}
+
+	return UNSPECIFIED, nil
+}
+
+// evalBegin evaluates the expressions in order, the last is a tail call
+func evalBegin(node *Pair, env *Environment) (Object, *Environment) {
+	return evalSequence(node.Cdr, env)
+}
+
+// evalClauseBody handles both the (=> receiver) and expression... forms of a
+// COND or CASE clause body
+func evalClauseBody(body Object, value Object, env *Environment) (Object, *Environment) {
+	if pair, ok := body.(*Pair); ok && isSymbol(pair.Car, "=>") {
+		if pair.Cdr == NIL {
+			return newError("Malformed => clause expecting (=> receiver)"), nil
+		}
+
+		proc := Eval(pair.Cdr.(*Pair).Car, env)
+		if isError(proc) {
+			return proc, nil
+		}
+
+		return applyProcedure(proc, "=>", []Object{value}, env), nil
+	}
+
+	return evalSequence(body, env)
+}
+
+// evalSequence evaluates all but the last expression, the last is returned
+// for the caller to evaluate in tail position
+func evalSequence(body Object, env *Environment) (Object, *Environment) {
+	if body == NIL {
+		return UNSPECIFIED, nil
+	}
+
+	for {
+		cell, ok := body.(*Pair)
+		if !ok {
+			return newError("expecting a proper list"), nil
+		}
+
+		if cell.Cdr == NIL {
+			return evalTail(cell, env)
+		}
+
+		result := evalAt(cell.Car, cell.CarPos, env)
+		if isError(result) {
+			return result, nil
+		}
+
+		body = cell.Cdr
+	}
+}
+
+// applyProcedure calls any kind of procedure with already evaluated args
+func applyProcedure(proc Object, name string, args []Object, env *Environment) Object {
+	switch fn := proc.(type) {
+	case *Builtin:
+		return fn.Fn(args...)
+	case *ScopedBuiltin:
+		return fn.Fn(env, args...)
+	case *Continuation:
+		return throw(fn, args, env)
+	case *Lambda, *CaseLambda:
+		lambda, err := selectLambda(fn, name, args)
+		if err != nil {
+			return err
+		}
+
+		return applyFunction(lambda, name, args)
+	}
+
+	return newError(fmt.Sprintf("%s is not a procedure", proc.Inspect()))
+}
+
+// selectLambda picks the CASE-LAMBDA clause accepting args and checks the
+// number of arguments, errors name the procedure being called
+func selectLambda(proc Object, name string, args []Object) (*Lambda, *Error) {
+	switch fn := proc.(type) {
+	case *Lambda:
+		if fn.Name != "" {
+			name = fn.Name
+		}
+
+		if len(args) < len(fn.Parameters) || (fn.Rest == nil && len(args) > len(fn.Parameters)) {
+			if fn.Rest != nil {
+				return nil, errorObject(fmt.Errorf("%s expecting at least %d argument(s) got %d", name, len(fn.Parameters), len(args)))
+			}
+
+			return nil, errorObject(fmt.Errorf("%s expecting %d argument(s) got %d", name, len(fn.Parameters), len(args)))
+		}
+
+		return fn, nil
+	case *CaseLambda:
+		for _, clause := range fn.Clauses {
+			if len(args) == len(clause.Parameters) || (clause.Rest != nil && len(args) > len(clause.Parameters)) {
+				return clause, nil
+			}
 		}
+
+		if fn.Name != "" {
+			name = fn.Name
+		}
+
+		return nil, errorObject(fmt.Errorf("%s no clause accepts %d argument(s)", name, len(args)))
 	}
 
-	panic("You just found a bug or an unimplemented feature congrats!")
+	return nil, errorObject(fmt.Errorf("%s is not a procedure", proc.Inspect()))
 }
 
 func applyFunction(lambda *Lambda, name string, args []Object) Object {
+	depth := len(callStack)
+	callStack = append(callStack, callFrame{Name: procedureName(lambda, "#<procedure>")})
+	defer func() { callStack = callStack[:depth] }()
+
+	outer := formPos
+	formPos = nil
+	defer func() { formPos = outer }()
+
 	extendedEnv := extendFunctionEnv(lambda, name, args)
 
-	return Eval(lambda.Body, extendedEnv)
+	body, tailEnv := evalSequence(lambda.Body, extendedEnv)
+	if tailEnv == nil {
+		return body
+	}
+
+	return Eval(body, extendedEnv)
+}
+
+// newLambda parses the parameter list and creates a closure over env, the
+// parameters may be a single identifier or end in . rest
+func newLambda(params Object, body Object, env *Environment) (*Lambda, *Error) {
+	if body == NIL {
+		return nil, newError("Expecting at least one expression in body")
+	}
+
+	lambda := &Lambda{Parameters: []*Identifier{}, Body: body, Env: env}
+
+	for params != NIL {
+		var param Object
+
+		switch node := params.(type) {
+		case *Pair:
+			param, params = node.Car, node.Cdr
+		case *Identifier:
+			lambda.Rest = node
+			return lambda, nil
+		default:
+			param, params = node, NIL
+		}
+
+		ident, ok := param.(*Identifier)
+		if !ok {
+			return nil, errorObject(fmt.Errorf("Expecting an identifier as a parameter found %s", param.Inspect()))
+		}
+
+		lambda.Parameters = append(lambda.Parameters, ident)
+	}
+
+	return lambda, nil
 }
 
 func extendFunctionEnv(lambda *Lambda, name string, args []Object) *Environment {
@@ -113,11 +685,15 @@ func extendFunctionEnv(lambda *Lambda, name string, args []Object) *Environment
 		env.Set(param.Value, args[paramIdx])
 	}
 
+	if lambda.Rest != nil {
+		env.Set(lambda.Rest.Value, sliceToList(args[len(lambda.Parameters):], NIL))
+	}
+
 	return env
 }
 
 func errorObject(err error) *Error {
-	return &Error{Value: err}
+	return &Error{Value: err, Backtrace: backtrace()}
 }
 
 func newError(msg string) *Error {
@@ -132,21 +708,192 @@ func apMsg(msg string, any interface{}) {
 	fmt.Println(fmt.Printf("%s: %#v", msg, any))
 }
 
-func evalArgs(pair *Pair, env *Environment) ([]Object, *Error) {
-	args := []Object{}
+// isTruthy everything but #F is true
+func isTruthy(obj Object) bool {
+	return obj != FALSE
+}
+
+// isSymbol checks obj is the identifier named name
+func isSymbol(obj Object, name string) bool {
+	ident, ok := obj.(*Identifier)
+	return ok && ident.Value == name
+}
+
+// isEqv compares objects the way eqv? does, symbols are interned so they
+// compare by identity
+func isEqv(a, b Object) bool {
+	if a == b {
+		return true
+	}
+
+	if isNumber(a) && isNumber(b) {
+		return numbersEqv(a, b)
+	}
+
+	switch left := a.(type) {
+	case *Char:
+		right, ok := b.(*Char)
+		return ok && left.Value == right.Value
+	case *Boolean:
+		right, ok := b.(*Boolean)
+		return ok && left.Value == right.Value
+	}
+
+	return false
+}
 
+// isEq compares by identity, small integers and characters with the same
+// value are the same object
+func isEq(a, b Object) bool {
+	if left, ok := a.(*Integer); ok {
+		right, ok := b.(*Integer)
+		return ok && left.Value == right.Value
+	}
+
+	if isNumber(a) {
+		return a == b
+	}
+
+	return isEqv(a, b)
+}
+
+// equalBudget is how many pairs and vectors equal? compares before it starts
+// remembering them to detect cycles
+const equalBudget = 1000
+
+// equality compares structures, once over budget a pair of objects already
+// being compared is assumed equal so cyclic structures terminate
+type equality struct {
+	steps   int
+	visited map[[2]Object]bool
+}
+
+// isEqual compares pairs, vectors, strings and bytevectors by their contents
+func isEqual(a, b Object) bool {
+	return (&equality{}).equal(a, b)
+}
+
+func (c *equality) equal(a, b Object) bool {
 	for {
-		car := pair.Car
-		val := Eval(car, env)
+		if a == b {
+			return true
+		}
+
+		switch left := a.(type) {
+		case *Pair:
+			right, ok := b.(*Pair)
+			if !ok {
+				return false
+			}
+
+			if c.seen(left, right) {
+				return true
+			}
+
+			if !c.equal(left.Car, right.Car) {
+				return false
+			}
+
+			a, b = left.Cdr, right.Cdr
+			continue
+		case *Vector:
+			right, ok := b.(*Vector)
+			if !ok || len(left.Value) != len(right.Value) {
+				return false
+			}
+
+			if c.seen(left, right) {
+				return true
+			}
+
+			for idx := range left.Value {
+				if !c.equal(left.Value[idx], right.Value[idx]) {
+					return false
+				}
+			}
+
+			return true
+		case *String:
+			right, ok := b.(*String)
+			return ok && left.Value == right.Value
+		case *Bytevector:
+			right, ok := b.(*Bytevector)
+			return ok && bytes.Equal(left.Value, right.Value)
+		}
+
+		return isEqv(a, b)
+	}
+}
+
+// seen records a and b as being compared and reports if they already were
+func (c *equality) seen(a, b Object) bool {
+	c.steps++
+	if c.steps < equalBudget {
+		return false
+	}
+
+	if c.visited == nil {
+		c.visited = map[[2]Object]bool{}
+	}
+
+	key := [2]Object{a, b}
+	if c.visited[key] {
+		return true
+	}
+
+	c.visited[key] = true
+	return false
+}
+
+// isString checks obj is a string
+func isString(obj Object) bool {
+	_, ok := obj.(*String)
+	return ok
+}
+
+// listToSlice converts a proper list into a slice
+func listToSlice(obj Object) ([]Object, *Error) {
+	list := []Object{}
+
+	for obj != NIL {
+		pair, ok := obj.(*Pair)
+		if !ok {
+			return nil, newError("expecting a proper list")
+		}
+
+		list = append(list, pair.Car)
+		obj = pair.Cdr
+	}
+
+	return list, nil
+}
+
+// sliceToList builds a list from objs ending in tail
+func sliceToList(objs []Object, tail Object) Object {
+	list := tail
+
+	for idx := len(objs) - 1; idx >= 0; idx-- {
+		list = &Pair{Car: objs[idx], Cdr: list}
+	}
+
+	return list
+}
+
+func evalArgs(obj Object, env *Environment) ([]Object, *Error) {
+	args := []Object{}
+
+	for obj != NIL {
+		cell, ok := obj.(*Pair)
+		if !ok {
+			return nil, newError("expecting a proper list")
+		}
+
+		val := evalAt(cell.Car, cell.CarPos, env)
 		if isError(val) {
 			return nil, val.(*Error)
 		}
 		args = append(args, val)
-		if pair.Cdr != nil {
-			pair = pair.Cdr.(*Pair)
-		} else {
-			break
-		}
+		obj = cell.Cdr
 	}
 
 	return args, nil
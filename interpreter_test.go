@@ -0,0 +1,161 @@
+package main
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+// evalProgram evaluates every expression of program in env and returns the
+// value of the last one, failing the test on the first error
+func evalProgram(t *testing.T, env *Environment, program string) Object {
+	t.Helper()
+
+	var result Object = UNSPECIFIED
+	for _, obj := range NewNamedReader(program, t.Name()).ReadAll() {
+		result = Eval(Expand(obj, env), env)
+		if err, ok := result.(*Error); ok {
+			t.Fatalf("%s: %s", program, err.Inspect())
+		}
+	}
+
+	return result
+}
+
+func TestTailCallsRunInConstantStack(t *testing.T) {
+	// a loop that grows the Go stack per iteration overflows this limit
+	// long before a million iterations and crashes the test
+	defer debug.SetMaxStack(debug.SetMaxStack(1 << 20))
+
+	tests := []struct {
+		name    string
+		program string
+	}{
+		{"if", "(define (loop n) (if (= n 0) 'done (loop (- n 1)))) (loop 1000000)"},
+		{"cond", "(define (loop n) (cond ((= n 0) 'done) (else (loop (- n 1))))) (loop 1000000)"},
+		{"and", "(define (loop n) (if (= n 0) 'done (and #t (loop (- n 1))))) (loop 1000000)"},
+		{"or", "(define (loop n) (if (= n 0) 'done (or #f (loop (- n 1))))) (loop 1000000)"},
+		{"begin", "(define (loop n) (if (= n 0) 'done (begin n (loop (- n 1))))) (loop 1000000)"},
+		{"named let", "(let loop ((n 1000000)) (if (= n 0) 'done (loop (- n 1))))"},
+		{"do", "(do ((n 1000000 (- n 1))) ((= n 0) 'done))"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := evalProgram(t, Load(), test.program)
+			if result != intern("DONE") {
+				t.Errorf("expected DONE got %s", result.Inspect())
+			}
+		})
+	}
+}
+
+func TestClosuresKeepSeparateEnvironments(t *testing.T) {
+	env := Load()
+	evalProgram(t, env, `
+		(define (make-adder n) (lambda (x) (+ x n)))
+		(define (make-counter)
+		  (let ((count 0))
+		    (lambda () (set! count (+ count 1)) count)))
+		(define add1 (make-adder 1))
+		(define add10 (make-adder 10))
+		(define a (make-counter))
+		(define b (make-counter))
+		(a) (a) (b)`)
+
+	tests := []struct {
+		program  string
+		expected int64
+	}{
+		{"(add1 1)", 2},
+		{"(add10 1)", 11},
+		{"((make-adder 1) 1)", 2},
+		{"((make-adder 10) 1)", 11},
+		{"(a)", 3},
+		{"(b)", 2},
+	}
+
+	for _, test := range tests {
+		result, ok := evalProgram(t, env, test.program).(*Integer)
+		if !ok || result.Value != test.expected {
+			t.Errorf("%s expected %d got %v", test.program, test.expected, result)
+		}
+	}
+
+	if evalProgram(t, env, "(eq? add1 add10)") != FALSE {
+		t.Errorf("expected each evaluation of a lambda expression to create a new procedure")
+	}
+}
+
+func TestMacroFreeIdentifiersIgnoreLocalBindings(t *testing.T) {
+	env := Load()
+	evalProgram(t, env, `
+		(define (helper) 'global)
+		(define-syntax call-helper (syntax-rules () ((_) (helper))))`)
+
+	tests := []struct {
+		name    string
+		program string
+	}{
+		{"let", "(let ((helper (lambda () 'local))) (call-helper))"},
+		{"lambda", "((lambda (helper) (call-helper)) (lambda () 'local))"},
+		{"internal define", "((lambda () (define (helper) 'local) (call-helper)))"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := evalProgram(t, env, test.program)
+			if result != intern("GLOBAL") {
+				t.Errorf("expected GLOBAL got %s", result.Inspect())
+			}
+		})
+	}
+}
+
+func TestErrorsReachHandlersBeforeUnwinding(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"error", `(error "x")`},
+		{"internal error", "(car 1)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := evalProgram(t, Load(), `
+				(with-output-to-string
+				  (lambda ()
+				    (call/cc
+				      (lambda (k)
+				        (with-exception-handler
+				          (lambda (e) (display "handler ") (k 0))
+				          (lambda ()
+				            (dynamic-wind
+				              (lambda () (display "in "))
+				              (lambda () `+test.body+`)
+				              (lambda () (display "out ")))))))))`)
+
+			if str, ok := result.(*String); !ok || str.Value != "in handler out " {
+				t.Errorf(`expected "in handler out " got %s`, result.Inspect())
+			}
+		})
+	}
+}
+
+func TestAtomErrorsReportWhereTheyWereRead(t *testing.T) {
+	env := Load()
+
+	var result Object
+	for _, obj := range NewNamedReader("(define (f)\n  x)\n(f)", "t.scm").ReadAll() {
+		result = Eval(Expand(obj, env), env)
+	}
+
+	err, ok := result.(*Error)
+	if !ok || len(err.Backtrace) == 0 {
+		t.Fatalf("expected an error with a backtrace got %s", result.Inspect())
+	}
+
+	if frame := err.Backtrace[0].String(); frame != "X (t.scm:2:3)" {
+		t.Errorf("expected X (t.scm:2:3) got %s", frame)
+	}
+}
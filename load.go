@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// commandLine holds the script name and its arguments for COMMAND-LINE
+var commandLine = os.Args
+
+// LoadFile reads every expression in the file at path and evaluates them in
+// order into env, it returns the value of the last one
+func LoadFile(path string, env *Environment) Object {
+	file, err := os.Open(path)
+	if err != nil {
+		return fileError("LOAD", err)
+	}
+
+	defer file.Close()
+
+	var result Object = UNSPECIFIED
+	for _, obj := range NewBufferedReader(bufio.NewReader(file), path).ReadAll() {
+		if isError(obj) {
+			return obj
+		}
+
+		result = Eval(Expand(obj, env), env)
+		if isError(result) {
+			return result
+		}
+	}
+
+	return result
+}
+
+// exitCode converts the argument of EXIT into a process status, true or no
+// argument is success and false is failure
+func exitCode(args []Object) (int, *Error) {
+	if len(args) > 1 {
+		return 0, newError("EXIT expecting at most one argument")
+	}
+
+	if len(args) == 0 || args[0] == TRUE {
+		return 0, nil
+	}
+
+	if args[0] == FALSE {
+		return 1, nil
+	}
+
+	if code, ok := args[0].(*Integer); ok {
+		return int(code.Value), nil
+	}
+
+	return 0, errorObject(fmt.Errorf("EXIT expecting an integer or boolean found %s", args[0].Inspect()))
+}
+
+// exit runs the after thunks of every active DYNAMIC-WIND, flushes the
+// output ports and ends the process with code
+func exit(code int, env *Environment) Object {
+	for winds != nil {
+		after := winds.after
+		winds = winds.outer
+
+		if result := applyProcedure(after, "EXIT", []Object{}, env); isError(result) {
+			return result
+		}
+	}
+
+	flushFiles()
+	currentOutput.flush()
+	currentError.flush()
+	os.Exit(code)
+
+	return UNSPECIFIED
+}
@@ -0,0 +1,979 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// gensymCounter numbers the identifiers created when renaming introduced
+// bindings, the lowercase g keeps them apart from anything the reader makes
+var gensymCounter = 0
+
+// alias is an identifier introduced by a macro template
+type alias struct {
+	original *Identifier
+	env      *Environment
+}
+
+// expander holds the renaming state for expanding one top level form
+type expander struct {
+	aliases map[*Identifier]*alias
+	renamed map[*Identifier]*Identifier
+}
+
+// binding is what a pattern variable matched, under an ellipsis items holds
+// one binding per repetition
+type binding struct {
+	form  Object
+	items []*binding
+}
+
+// Expand rewrites every macro use in obj into core forms, env receives top
+// level DEFINE-SYNTAX definitions
+func Expand(obj Object, env *Environment) Object {
+	e := &expander{aliases: map[*Identifier]*alias{}, renamed: map[*Identifier]*Identifier{}}
+	return e.expand(obj, env)
+}
+
+func (e *expander) expand(obj Object, env *Environment) Object {
+	switch node := obj.(type) {
+	case *Identifier:
+		return e.free(node)
+	case *Vector:
+		return e.strip(node)
+	case *Pair:
+		ident, ok := node.Car.(*Identifier)
+		if !ok {
+			return e.expandList(node, env)
+		}
+
+		if macro := e.lookupMacro(ident, env); macro != nil {
+			expanded := e.transcribe(macro, node)
+			if isError(expanded) {
+				return expanded
+			}
+
+			if pair, ok := expanded.(*Pair); ok && pair.Pos == nil {
+				pair.Pos = node.Pos
+			}
+
+			return e.expand(expanded, env)
+		}
+
+		if e.isRenamed(ident) {
+			return e.expandList(node, env)
+		}
+
+		switch e.name(ident) {
+		case "QUOTE":
+			return &Pair{Car: &Identifier{Value: "QUOTE"}, Cdr: e.strip(node.Cdr)}
+		case "QUASIQUOTE":
+			return &Pair{Car: &Identifier{Value: "QUASIQUOTE"}, Cdr: e.expandQuasiquote(node.Cdr, 1, env)}
+		case "LAMBDA":
+			return e.expandLambda(node, env)
+		case "CASE-LAMBDA":
+			return e.expandCaseLambda(node, env)
+		case "DEFINE":
+			return e.expandDefine(node, env)
+		case "DEFINE-SYNTAX":
+			return e.expandDefineSyntax(node, env)
+		case "LET-SYNTAX":
+			return e.expandLetSyntax(node, env, false)
+		case "LETREC-SYNTAX":
+			return e.expandLetSyntax(node, env, true)
+		case "COND":
+			return e.expandCond(node, env)
+		case "CASE":
+			return e.expandCase(node, env)
+		case "GUARD":
+			return e.expandGuard(node, env)
+		case "DEFINE-RECORD-TYPE":
+			return e.free(node)
+		}
+
+		return e.expandList(node, env)
+	}
+
+	return obj
+}
+
+// expandList expands every element of a list including an improper tail
+func (e *expander) expandList(obj Object, env *Environment) Object {
+	pair, ok := obj.(*Pair)
+	if !ok {
+		return e.expand(obj, env)
+	}
+
+	car := e.expand(pair.Car, env)
+	if isError(car) {
+		return car
+	}
+
+	cdr := e.expandList(pair.Cdr, env)
+	if isError(cdr) {
+		return cdr
+	}
+
+	return &Pair{Car: car, Cdr: cdr, Pos: pair.Pos, CarPos: pair.CarPos}
+}
+
+// expandLambda renames introduced parameters and expands the body in a frame
+// where the parameters shadow any macros
+func (e *expander) expandLambda(node *Pair, env *Environment) Object {
+	rest, ok := node.Cdr.(*Pair)
+	if !ok {
+		return newError("Malformed LAMBDA expecting (LAMBDA (params...) body...)")
+	}
+
+	params, body := e.renameBinders(rest.Car, rest.Cdr)
+	frame := NewEnclosedEnvironment(env)
+	e.bindPlaceholders(params, frame)
+
+	expanded := e.expandBody(body, frame)
+	if isError(expanded) {
+		return expanded
+	}
+
+	return &Pair{Car: &Identifier{Value: "LAMBDA"}, Cdr: &Pair{Car: e.free(params), Cdr: expanded}}
+}
+
+// expandCaseLambda expands each clause like a LAMBDA
+func (e *expander) expandCaseLambda(node *Pair, env *Environment) Object {
+	clauses, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err
+	}
+
+	expanded := []Object{}
+	for _, clause := range clauses {
+		lambda := e.expandLambda(&Pair{Car: node.Car, Cdr: clause}, env)
+		if isError(lambda) {
+			return lambda
+		}
+
+		expanded = append(expanded, lambda.(*Pair).Cdr)
+	}
+
+	return &Pair{Car: &Identifier{Value: "CASE-LAMBDA"}, Cdr: sliceToList(expanded, NIL)}
+}
+
+// expandDefine expands the value of a DEFINE, the procedure shorthand is
+// expanded like a LAMBDA
+func (e *expander) expandDefine(node *Pair, env *Environment) Object {
+	rest, ok := node.Cdr.(*Pair)
+	if !ok {
+		return newError("Malformed DEFINE expecting (DEFINE name value)")
+	}
+
+	if signature, ok := rest.Car.(*Pair); ok {
+		lambda := e.expandLambda(&Pair{Car: node.Car, Cdr: &Pair{Car: signature.Cdr, Cdr: rest.Cdr}}, env)
+		if isError(lambda) {
+			return lambda
+		}
+
+		params := lambda.(*Pair).Cdr.(*Pair)
+		name := e.free(signature.Car)
+		return &Pair{Car: &Identifier{Value: "DEFINE"}, Cdr: &Pair{Car: &Pair{Car: name, Cdr: params.Car}, Cdr: params.Cdr}}
+	}
+
+	value := e.expandList(rest.Cdr, env)
+	if isError(value) {
+		return value
+	}
+
+	return &Pair{Car: &Identifier{Value: "DEFINE"}, Cdr: &Pair{Car: e.free(rest.Car), Cdr: value}}
+}
+
+// expandDefineSyntax binds the macro in env, it leaves an empty BEGIN behind
+func (e *expander) expandDefineSyntax(node *Pair, env *Environment) Object {
+	args, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 2 {
+		return newError("Malformed DEFINE-SYNTAX expecting (DEFINE-SYNTAX name transformer)")
+	}
+
+	ident, ok := args[0].(*Identifier)
+	if !ok {
+		return errorObject(fmt.Errorf("DEFINE-SYNTAX expecting an identifier found %s", args[0].Inspect()))
+	}
+
+	macro := e.makeMacro(e.name(ident), args[1], env)
+	if isError(macro) {
+		return macro
+	}
+
+	env.Set(e.name(ident), macro)
+	return &Pair{Car: &Identifier{Value: "BEGIN"}, Cdr: NIL}
+}
+
+// expandLetSyntax binds macros in a new frame around the body, with rec the
+// transformers can refer to each other
+func (e *expander) expandLetSyntax(node *Pair, env *Environment, rec bool) Object {
+	rest, ok := node.Cdr.(*Pair)
+	if !ok {
+		return newError("Malformed LET-SYNTAX expecting (LET-SYNTAX ((name transformer)...) body...)")
+	}
+
+	bindings, err := listToSlice(rest.Car)
+	if err != nil {
+		return err
+	}
+
+	frame := NewEnclosedEnvironment(env)
+	macroEnv := env
+	if rec {
+		macroEnv = frame
+	}
+
+	for _, obj := range bindings {
+		spec, err := listToSlice(obj)
+		if err != nil {
+			return err
+		}
+
+		if len(spec) != 2 {
+			return newError("Malformed LET-SYNTAX binding expecting (name transformer)")
+		}
+
+		ident, ok := spec[0].(*Identifier)
+		if !ok {
+			return newError("Malformed LET-SYNTAX binding expecting (name transformer)")
+		}
+
+		macro := e.makeMacro(e.name(ident), spec[1], macroEnv)
+		if isError(macro) {
+			return macro
+		}
+
+		frame.Set(e.name(ident), macro)
+	}
+
+	body := e.expandBody(rest.Cdr, frame)
+	if isError(body) {
+		return body
+	}
+
+	lambda := &Pair{Car: &Identifier{Value: "LAMBDA"}, Cdr: &Pair{Car: NIL, Cdr: body}}
+	return &Pair{Car: lambda, Cdr: NIL}
+}
+
+// expandBody expands a body where internal defines are renamed and every
+// defined name shadows macros of the same name
+func (e *expander) expandBody(body Object, frame *Environment) Object {
+	forms, err := listToSlice(body)
+	if err != nil {
+		return err
+	}
+
+	for _, form := range forms {
+		name := e.definedName(form, frame)
+		if name == nil {
+			continue
+		}
+
+		gensym := e.gensym(name)
+		body = substitute(body, name, gensym)
+		frame.Set(gensym.Value, nil)
+	}
+
+	return e.expandList(body, frame)
+}
+
+// definedName returns the name bound by a body level DEFINE
+func (e *expander) definedName(form Object, env *Environment) *Identifier {
+	pair, ok := form.(*Pair)
+	if !ok {
+		return nil
+	}
+
+	ident, ok := pair.Car.(*Identifier)
+	if !ok || e.name(ident) != "DEFINE" || e.lookupMacro(ident, env) != nil {
+		return nil
+	}
+
+	if e.isRenamed(ident) {
+		return nil
+	}
+
+	rest, ok := pair.Cdr.(*Pair)
+	if !ok {
+		return nil
+	}
+
+	if signature, ok := rest.Car.(*Pair); ok {
+		name, _ := signature.Car.(*Identifier)
+		return name
+	}
+
+	name, _ := rest.Car.(*Identifier)
+	return name
+}
+
+// renameBinders gives every identifier in params a fresh name and substitutes
+// it throughout body, so a local variable never captures a free identifier a
+// macro introduces and Eval never mistakes it for a special form
+func (e *expander) renameBinders(params Object, body Object) (Object, Object) {
+	for obj := params; ; {
+		var ident *Identifier
+
+		switch node := obj.(type) {
+		case *Pair:
+			ident, _ = node.Car.(*Identifier)
+			obj = node.Cdr
+		case *Identifier:
+			ident = node
+			obj = nil
+		default:
+			obj = nil
+		}
+
+		if ident != nil {
+			gensym := e.gensym(ident)
+			params = substitute(params, ident, gensym)
+			body = substitute(body, ident, gensym)
+		}
+
+		if obj == nil {
+			break
+		}
+	}
+
+	return params, body
+}
+
+// bindPlaceholders marks parameters as variables so they shadow macros
+func (e *expander) bindPlaceholders(params Object, frame *Environment) {
+	for obj := params; ; {
+		switch node := obj.(type) {
+		case *Pair:
+			if ident, ok := node.Car.(*Identifier); ok {
+				frame.Set(ident.Value, nil)
+			}
+
+			obj = node.Cdr
+			continue
+		case *Identifier:
+			frame.Set(node.Value, nil)
+		}
+
+		return
+	}
+}
+
+// expandCond expands the tests and bodies of each clause
+func (e *expander) expandCond(node *Pair, env *Environment) Object {
+	clauses, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err
+	}
+
+	expanded := []Object{}
+	for _, clause := range clauses {
+		result := e.expandList(clause, env)
+		if isError(result) {
+			return result
+		}
+
+		expanded = append(expanded, result)
+	}
+
+	return &Pair{Car: &Identifier{Value: "COND"}, Cdr: sliceToList(expanded, NIL)}
+}
+
+// expandGuard renames an introduced condition variable, the clauses are
+// expanded with it bound and the body like a LAMBDA body
+func (e *expander) expandGuard(node *Pair, env *Environment) Object {
+	rest, ok := node.Cdr.(*Pair)
+	if !ok {
+		return newError("Malformed GUARD expecting (GUARD (var clause...) body...)")
+	}
+
+	spec, ok := rest.Car.(*Pair)
+	if !ok {
+		return newError("Malformed GUARD expecting (GUARD (var clause...) body...)")
+	}
+
+	variable, clauses := e.renameBinders(&Pair{Car: spec.Car, Cdr: NIL}, spec.Cdr)
+	frame := NewEnclosedEnvironment(env)
+	e.bindPlaceholders(variable, frame)
+
+	expandedClauses := e.expandCond(&Pair{Car: &Identifier{Value: "COND"}, Cdr: clauses}, frame)
+	if isError(expandedClauses) {
+		return expandedClauses
+	}
+
+	body := e.expandBody(rest.Cdr, NewEnclosedEnvironment(env))
+	if isError(body) {
+		return body
+	}
+
+	spec = &Pair{Car: e.free(variable.(*Pair).Car), Cdr: expandedClauses.(*Pair).Cdr}
+	return &Pair{Car: &Identifier{Value: "GUARD"}, Cdr: &Pair{Car: spec, Cdr: body}}
+}
+
+// expandCase expands the key and clause bodies leaving the datums alone
+func (e *expander) expandCase(node *Pair, env *Environment) Object {
+	args, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return newError("Malformed CASE expecting (CASE key clause...)")
+	}
+
+	expanded := []Object{e.expand(args[0], env)}
+	if isError(expanded[0]) {
+		return expanded[0]
+	}
+
+	for _, obj := range args[1:] {
+		clause, ok := obj.(*Pair)
+		if !ok {
+			return newError("Malformed CASE clause expecting ((datum...) expression...)")
+		}
+
+		body := e.expandList(clause.Cdr, env)
+		if isError(body) {
+			return body
+		}
+
+		expanded = append(expanded, &Pair{Car: e.strip(clause.Car), Cdr: body})
+	}
+
+	return &Pair{Car: &Identifier{Value: "CASE"}, Cdr: sliceToList(expanded, NIL)}
+}
+
+// expandQuasiquote only expands the expressions that will be evaluated
+func (e *expander) expandQuasiquote(template Object, depth int, env *Environment) Object {
+	switch node := template.(type) {
+	case *Pair:
+		if ident, ok := node.Car.(*Identifier); ok {
+			switch name := e.name(ident); name {
+			case "UNQUOTE", "UNQUOTE-SPLICING":
+				if depth == 1 {
+					operands := e.expandList(node.Cdr, env)
+					if isError(operands) {
+						return operands
+					}
+
+					return &Pair{Car: intern(name), Cdr: operands}
+				}
+
+				return &Pair{Car: intern(name), Cdr: e.expandQuasiquote(node.Cdr, depth-1, env)}
+			case "QUASIQUOTE":
+				return &Pair{Car: intern(name), Cdr: e.expandQuasiquote(node.Cdr, depth+1, env)}
+			}
+		}
+
+		car := e.expandQuasiquote(node.Car, depth, env)
+		if isError(car) {
+			return car
+		}
+
+		cdr := e.expandQuasiquote(node.Cdr, depth, env)
+		if isError(cdr) {
+			return cdr
+		}
+
+		return &Pair{Car: car, Cdr: cdr}
+	case *Vector:
+		list := e.expandQuasiquote(sliceToList(node.Value, NIL), depth, env)
+		if isError(list) {
+			return list
+		}
+
+		items, _ := listToSlice(list)
+		return &Vector{Value: items, Constant: node.Constant}
+	}
+
+	return e.strip(template)
+}
+
+// lookupMacro finds the macro an identifier in operator position refers to,
+// introduced identifiers are looked up where their macro was defined
+func (e *expander) lookupMacro(ident *Identifier, env *Environment) *Macro {
+	if info, ok := e.aliases[ident]; ok {
+		ident, env = info.original, info.env
+	}
+
+	if val, ok := env.Get(ident.Value); ok {
+		if macro, ok := val.(*Macro); ok {
+			return macro
+		}
+	}
+
+	return nil
+}
+
+// name returns the name an identifier was written with before any renaming
+func (e *expander) name(ident *Identifier) string {
+	if info, ok := e.aliases[ident]; ok {
+		return e.name(info.original)
+	}
+
+	if original, ok := e.renamed[ident]; ok {
+		return original.Value
+	}
+
+	return ident.Value
+}
+
+// isRenamed checks ident, or the identifier it was copied from, is a local
+// variable given a fresh name
+func (e *expander) isRenamed(ident *Identifier) bool {
+	if info, ok := e.aliases[ident]; ok {
+		ident = info.original
+	}
+
+	_, ok := e.renamed[ident]
+	return ok
+}
+
+// free replaces introduced identifiers that were never bound with the
+// identifier they were copied from
+func (e *expander) free(obj Object) Object {
+	switch node := obj.(type) {
+	case *Identifier:
+		if info, ok := e.aliases[node]; ok {
+			return info.original
+		}
+	case *Pair:
+		car, cdr := e.free(node.Car), e.free(node.Cdr)
+		if car != node.Car || cdr != node.Cdr {
+			return &Pair{Car: car, Cdr: cdr}
+		}
+	case *Vector:
+		items := make([]Object, len(node.Value))
+		changed := false
+
+		for idx, item := range node.Value {
+			items[idx] = e.free(item)
+			changed = changed || items[idx] != item
+		}
+
+		if changed {
+			return &Vector{Value: items, Constant: node.Constant}
+		}
+	}
+
+	return obj
+}
+
+// strip restores the original names inside quoted data
+func (e *expander) strip(obj Object) Object {
+	switch node := obj.(type) {
+	case *Identifier:
+		if info, ok := e.aliases[node]; ok {
+			return e.strip(info.original)
+		}
+
+		if original, ok := e.renamed[node]; ok {
+			return original
+		}
+	case *Pair:
+		car, cdr := e.strip(node.Car), e.strip(node.Cdr)
+		if car != node.Car || cdr != node.Cdr {
+			return &Pair{Car: car, Cdr: cdr}
+		}
+	case *Vector:
+		items := make([]Object, len(node.Value))
+		changed := false
+
+		for idx, item := range node.Value {
+			items[idx] = e.strip(item)
+			changed = changed || items[idx] != item
+		}
+
+		if changed {
+			return &Vector{Value: items, Constant: node.Constant}
+		}
+	}
+
+	return obj
+}
+
+func (e *expander) gensym(ident *Identifier) *Identifier {
+	gensymCounter++
+	original := intern(e.name(ident))
+	gensym := &Identifier{Value: fmt.Sprintf("%s.g%d", original.Value, gensymCounter)}
+	e.renamed[gensym] = original
+
+	return gensym
+}
+
+// originalName drops the suffix gensym adds so errors and backtraces show the
+// name the program used
+func originalName(name string) string {
+	index := strings.LastIndex(name, ".g")
+	if index <= 0 || index+2 == len(name) {
+		return name
+	}
+
+	for _, digit := range name[index+2:] {
+		if digit < '0' || digit > '9' {
+			return name
+		}
+	}
+
+	return name[:index]
+}
+
+// substitute replaces every occurrence of from with to rebuilding the pairs
+// and vectors it passes through
+func substitute(obj Object, from, to *Identifier) Object {
+	switch node := obj.(type) {
+	case *Identifier:
+		if node == from {
+			return to
+		}
+	case *Pair:
+		car, cdr := substitute(node.Car, from, to), substitute(node.Cdr, from, to)
+		if car != node.Car || cdr != node.Cdr {
+			return &Pair{Car: car, Cdr: cdr, Pos: node.Pos, CarPos: node.CarPos}
+		}
+	case *Vector:
+		items := make([]Object, len(node.Value))
+		changed := false
+
+		for idx, item := range node.Value {
+			items[idx] = substitute(item, from, to)
+			changed = changed || items[idx] != item
+		}
+
+		if changed {
+			return &Vector{Value: items, Constant: node.Constant}
+		}
+	}
+
+	return obj
+}
+
+// makeMacro builds a macro from a (SYNTAX-RULES ...) transformer
+func (e *expander) makeMacro(name string, spec Object, env *Environment) Object {
+	spec = e.free(spec)
+
+	args, err := listToSlice(spec)
+	if err != nil || len(args) < 2 || !isSymbol(args[0], "SYNTAX-RULES") {
+		return errorObject(fmt.Errorf("%s expecting a (SYNTAX-RULES (literals...) rules...) transformer", name))
+	}
+
+	macro := &Macro{Name: name, Ellipsis: "...", Env: env}
+	args = args[1:]
+
+	if ellipsis, ok := args[0].(*Identifier); ok {
+		macro.Ellipsis = ellipsis.Value
+		args = args[1:]
+	}
+
+	if len(args) == 0 {
+		return errorObject(fmt.Errorf("%s expecting a list of literals", name))
+	}
+
+	literals, err := listToSlice(args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, literal := range literals {
+		ident, ok := literal.(*Identifier)
+		if !ok {
+			return errorObject(fmt.Errorf("%s expecting an identifier as a literal found %s", name, literal.Inspect()))
+		}
+
+		macro.Literals = append(macro.Literals, ident.Value)
+	}
+
+	for _, obj := range args[1:] {
+		rule, err := listToSlice(obj)
+		if err != nil || len(rule) != 2 {
+			return errorObject(fmt.Errorf("%s expecting rules of the form (pattern template)", name))
+		}
+
+		if _, ok := rule[0].(*Pair); !ok {
+			return errorObject(fmt.Errorf("%s expecting a list pattern found %s", name, rule[0].Inspect()))
+		}
+
+		macro.Rules = append(macro.Rules, [2]Object{rule[0], rule[1]})
+	}
+
+	return macro
+}
+
+// transcribe expands one use of macro with the first matching rule
+func (e *expander) transcribe(macro *Macro, form *Pair) Object {
+	for _, rule := range macro.Rules {
+		binds := map[string]*binding{}
+
+		if e.match(macro, rule[0].(*Pair).Cdr, form.Cdr, binds) {
+			return e.instantiate(macro, rule[1], binds, map[string]*Identifier{})
+		}
+	}
+
+	return errorObject(fmt.Errorf("No rule of %s matches %s", macro.Name, e.strip(form).Inspect()))
+}
+
+// match checks form against pattern recording pattern variables in binds
+func (e *expander) match(macro *Macro, pattern, form Object, binds map[string]*binding) bool {
+	switch pat := pattern.(type) {
+	case *Identifier:
+		if pat.Value == "_" {
+			return true
+		}
+
+		if macro.isLiteral(pat.Value) {
+			ident, ok := form.(*Identifier)
+			return ok && e.name(ident) == pat.Value
+		}
+
+		binds[pat.Value] = &binding{form: form}
+		return true
+	case *Pair:
+		if next, ok := pat.Cdr.(*Pair); ok && isSymbol(next.Car, macro.Ellipsis) {
+			return e.matchEllipsis(macro, pat.Car, next.Cdr, form, binds)
+		}
+
+		node, ok := form.(*Pair)
+		return ok && e.match(macro, pat.Car, node.Car, binds) && e.match(macro, pat.Cdr, node.Cdr, binds)
+	case *Vector:
+		node, ok := form.(*Vector)
+		return ok && e.match(macro, sliceToList(pat.Value, NIL), sliceToList(node.Value, NIL), binds)
+	case *EmptyList:
+		return form == NIL
+	case *String:
+		str, ok := form.(*String)
+		return ok && str.Value == pat.Value
+	}
+
+	return isEqv(pattern, form)
+}
+
+// matchEllipsis matches item as many times as possible leaving enough of
+// form for the patterns in rest
+func (e *expander) matchEllipsis(macro *Macro, item, rest, form Object, binds map[string]*binding) bool {
+	minimum := 0
+	for obj := rest; ; minimum++ {
+		pair, ok := obj.(*Pair)
+		if !ok {
+			break
+		}
+
+		obj = pair.Cdr
+	}
+
+	forms := []Object{}
+	for obj := form; ; {
+		pair, ok := obj.(*Pair)
+		if !ok {
+			break
+		}
+
+		forms = append(forms, pair.Car)
+		obj = pair.Cdr
+	}
+
+	count := len(forms) - minimum
+	if count < 0 {
+		return false
+	}
+
+	for _, name := range macro.patternVariables(item) {
+		binds[name] = &binding{items: []*binding{}}
+	}
+
+	for idx := 0; idx < count; idx++ {
+		itemBinds := map[string]*binding{}
+		if !e.match(macro, item, forms[idx], itemBinds) {
+			return false
+		}
+
+		for name, value := range itemBinds {
+			if _, ok := binds[name]; ok {
+				binds[name].items = append(binds[name].items, value)
+			}
+		}
+	}
+
+	tail := form
+	for idx := 0; idx < count; idx++ {
+		tail = tail.(*Pair).Cdr
+	}
+
+	return e.match(macro, rest, tail, binds)
+}
+
+// instantiate fills in template with the matched bindings, every other
+// identifier becomes an alias so bindings it introduces can be renamed
+func (e *expander) instantiate(macro *Macro, template Object, binds map[string]*binding, introduced map[string]*Identifier) Object {
+	switch node := template.(type) {
+	case *Identifier:
+		if value, ok := binds[node.Value]; ok {
+			if value.items != nil {
+				return errorObject(fmt.Errorf("%s pattern variable %s used without an ellipsis", macro.Name, node.Value))
+			}
+
+			return value.form
+		}
+
+		if ident, ok := introduced[node.Value]; ok {
+			return ident
+		}
+
+		ident := &Identifier{Value: node.Value}
+		e.aliases[ident] = &alias{original: node, env: macro.Env}
+		introduced[node.Value] = ident
+
+		return ident
+	case *Pair:
+		if isSymbol(node.Car, macro.Ellipsis) {
+			if escaped, ok := node.Cdr.(*Pair); ok {
+				return e.instantiate(&Macro{Name: macro.Name, Env: macro.Env}, escaped.Car, binds, introduced)
+			}
+		}
+
+		depth := 0
+		rest := node.Cdr
+		for {
+			next, ok := rest.(*Pair)
+			if !ok || !isSymbol(next.Car, macro.Ellipsis) {
+				break
+			}
+
+			depth++
+			rest = next.Cdr
+		}
+
+		tail := e.instantiate(macro, rest, binds, introduced)
+		if isError(tail) {
+			return tail
+		}
+
+		if depth == 0 {
+			car := e.instantiate(macro, node.Car, binds, introduced)
+			if isError(car) {
+				return car
+			}
+
+			return &Pair{Car: car, Cdr: tail}
+		}
+
+		items, err := e.instantiateEllipsis(macro, node.Car, depth, binds, introduced)
+		if err != nil {
+			return err
+		}
+
+		return sliceToList(items, tail)
+	case *Vector:
+		list := e.instantiate(macro, sliceToList(node.Value, NIL), binds, introduced)
+		if isError(list) {
+			return list
+		}
+
+		items, _ := listToSlice(list)
+		return &Vector{Value: items, Constant: node.Constant}
+	}
+
+	return template
+}
+
+// instantiateEllipsis instantiates item once per repetition of the pattern
+// variables it uses, depth is the number of ellipses following it
+func (e *expander) instantiateEllipsis(macro *Macro, item Object, depth int, binds map[string]*binding, introduced map[string]*Identifier) ([]Object, *Error) {
+	if depth == 0 {
+		result := e.instantiate(macro, item, binds, introduced)
+		if isError(result) {
+			return nil, result.(*Error)
+		}
+
+		return []Object{result}, nil
+	}
+
+	count := -1
+	vars := []string{}
+
+	for _, name := range macro.templateVariables(item, binds) {
+		if binds[name].items == nil {
+			continue
+		}
+
+		if count != -1 && count != len(binds[name].items) {
+			return nil, errorObject(fmt.Errorf("%s pattern variables under the same ellipsis matched different lengths", macro.Name))
+		}
+
+		count = len(binds[name].items)
+		vars = append(vars, name)
+	}
+
+	if count == -1 {
+		return nil, errorObject(fmt.Errorf("%s ellipsis follows a template without pattern variables", macro.Name))
+	}
+
+	results := []Object{}
+	for idx := 0; idx < count; idx++ {
+		itemBinds := map[string]*binding{}
+		for name, value := range binds {
+			itemBinds[name] = value
+		}
+
+		for _, name := range vars {
+			itemBinds[name] = binds[name].items[idx]
+		}
+
+		items, err := e.instantiateEllipsis(macro, item, depth-1, itemBinds, introduced)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, items...)
+	}
+
+	return results, nil
+}
+
+// isLiteral checks if name is one of the macro's literals
+func (m *Macro) isLiteral(name string) bool {
+	for _, literal := range m.Literals {
+		if literal == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// patternVariables lists the variables bound by pattern
+func (m *Macro) patternVariables(pattern Object) []string {
+	switch node := pattern.(type) {
+	case *Identifier:
+		if node.Value == "_" || node.Value == m.Ellipsis || m.isLiteral(node.Value) {
+			return nil
+		}
+
+		return []string{node.Value}
+	case *Pair:
+		return append(m.patternVariables(node.Car), m.patternVariables(node.Cdr)...)
+	case *Vector:
+		return m.patternVariables(sliceToList(node.Value, NIL))
+	}
+
+	return nil
+}
+
+// templateVariables lists the pattern variables used in template
+func (m *Macro) templateVariables(template Object, binds map[string]*binding) []string {
+	switch node := template.(type) {
+	case *Identifier:
+		if _, ok := binds[node.Value]; ok {
+			return []string{node.Value}
+		}
+	case *Pair:
+		return append(m.templateVariables(node.Car, binds), m.templateVariables(node.Cdr, binds)...)
+	case *Vector:
+		return m.templateVariables(sliceToList(node.Value, NIL), binds)
+	}
+
+	return nil
+}
@@ -1,37 +1,59 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
 )
 
 func main() {
+	expression := flag.String("e", "", "evaluate `EXPR` and exit")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: go-scheme [-e EXPR] [script.scm] [arguments...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	env := Load()
+
+	if *expression != "" {
+		commandLine = append([]string{os.Args[0]}, flag.Args()...)
+		os.Exit(run(NewNamedReader(*expression, "-e").ReadAll(), env))
+	}
+
+	if flag.NArg() > 0 {
+		commandLine = flag.Args()
+		os.Exit(status(LoadFile(flag.Arg(0), env)))
+	}
+
 	fmt.Println("Go Schemeing 1.0.0")
 	fmt.Println("Type .exit to exit")
-	replReader := bufio.NewReader(os.Stdin)
-	env := Load()
 
 	for {
 		fmt.Print(">> ")
-		text, _ := replReader.ReadString('\n')
+		text, err := stdin.ReadString('\n')
 		cleanText := strings.Trim(text, "\n")
-		if cleanText == ".exit" {
+		if cleanText == ".exit" || (err != nil && text == "") {
+			flushFiles()
 			break
 		}
 
-		reader := NewReader(text)
+		reader := NewNamedReader(text, "repl")
 		program := reader.ReadAll()
 
 		for _, obj := range program {
-			obj := Eval(obj, env)
-			if obj == nil {
-				break
+			obj := Eval(Expand(obj, env), env)
+			if obj == UNSPECIFIED {
+				continue
 			}
 
-			if isError(obj) {
-				fmt.Println(obj.Inspect())
+			if err, ok := obj.(*Error); ok {
+				fmt.Println(err.Inspect())
+				if len(err.Backtrace) > 0 {
+					fmt.Println(err.Trace())
+				}
+
 				break
 			}
 
@@ -40,6 +62,45 @@ func main() {
 	}
 }
 
+// run evaluates each expression given with -e printing its value
+func run(program []Object, env *Environment) int {
+	for _, obj := range program {
+		result := obj
+		if !isError(result) {
+			result = Eval(Expand(obj, env), env)
+		}
+
+		if isError(result) {
+			return status(result)
+		}
+
+		if result != UNSPECIFIED {
+			currentOutput.write(result.Inspect() + "\n")
+		}
+	}
+
+	return status(nil)
+}
+
+// status flushes the output and reports an error result on stderr, it
+// returns the exit status of the program
+func status(result Object) int {
+	flushFiles()
+	currentOutput.flush()
+
+	err, ok := result.(*Error)
+	if !ok {
+		return 0
+	}
+
+	fmt.Fprintln(os.Stderr, err.Inspect())
+	if len(err.Backtrace) > 0 {
+		fmt.Fprintln(os.Stderr, err.Trace())
+	}
+
+	return 1
+}
+
 func isError(obj Object) bool {
 	switch obj.(type) {
 	case *Error:
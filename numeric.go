@@ -0,0 +1,434 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// foldNumbers applies op from left to right starting with initial, it never
+// modifies its arguments and promotes operands up the numeric tower as needed
+func foldNumbers(op byte, initial Object, args []Object) Object {
+	result := initial
+	if !isNumber(result) {
+		return notANumber(result)
+	}
+
+	for _, arg := range args {
+		result = arithmetic(op, result, arg)
+		if isError(result) {
+			return result
+		}
+	}
+
+	return result
+}
+
+// arithmetic returns a new number holding left op right
+func arithmetic(op byte, left, right Object) Object {
+	if !isNumber(right) {
+		return notANumber(right)
+	}
+
+	leftInt, leftOk := left.(*Integer)
+	rightInt, rightOk := right.(*Integer)
+
+	if leftOk && rightOk && op != '/' {
+		if result, ok := fixnumArithmetic(op, leftInt.Value, rightInt.Value); ok {
+			return &Integer{Value: result}
+		}
+	}
+
+	if isExact(left) && isExact(right) {
+		a, b := toRat(left), toRat(right)
+		result := new(big.Rat)
+
+		switch op {
+		case '+':
+			result.Add(a, b)
+		case '-':
+			result.Sub(a, b)
+		case '*':
+			result.Mul(a, b)
+		case '/':
+			if b.Sign() == 0 {
+				return newError("Division by zero")
+			}
+
+			result.Quo(a, b)
+		}
+
+		return normalizeRat(result)
+	}
+
+	a, b := toFloat(left), toFloat(right)
+	switch op {
+	case '+':
+		return &Float{Value: a + b}
+	case '-':
+		return &Float{Value: a - b}
+	case '*':
+		return &Float{Value: a * b}
+	case '/':
+		return &Float{Value: a / b}
+	}
+
+	return newError(fmt.Sprintf("Unknown arithmetic operator %c", op))
+}
+
+// fixnumArithmetic reports false when the result does not fit in an int64
+func fixnumArithmetic(op byte, a, b int64) (int64, bool) {
+	switch op {
+	case '+':
+		sum := a + b
+		return sum, !((a > 0 && b > 0 && sum < 0) || (a < 0 && b < 0 && sum >= 0))
+	case '-':
+		diff := a - b
+		return diff, !((a >= 0 && b < 0 && diff < 0) || (a < 0 && b > 0 && diff >= 0))
+	case '*':
+		if a == 0 || b == 0 {
+			return 0, true
+		}
+
+		if (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+			return 0, false
+		}
+
+		product := a * b
+		return product, product/b == a
+	}
+
+	return 0, false
+}
+
+// compareNumbers checks that test holds for every adjacent pair of args
+func compareNumbers(args []Object, test func(cmp int) bool) Object {
+	if len(args) == 0 {
+		return newError("Expecting at least one number")
+	}
+
+	for _, arg := range args {
+		if !isNumber(arg) {
+			return notANumber(arg)
+		}
+
+		if f, ok := arg.(*Float); ok && math.IsNaN(f.Value) {
+			return FALSE
+		}
+	}
+
+	for idx := 1; idx < len(args); idx++ {
+		if !test(numberCompare(args[idx-1], args[idx])) {
+			return FALSE
+		}
+	}
+
+	return TRUE
+}
+
+// numberCompare returns -1, 0 or 1 comparing two numbers
+func numberCompare(left, right Object) int {
+	leftInt, leftOk := left.(*Integer)
+	rightInt, rightOk := right.(*Integer)
+
+	if leftOk && rightOk {
+		switch {
+		case leftInt.Value < rightInt.Value:
+			return -1
+		case leftInt.Value > rightInt.Value:
+			return 1
+		}
+
+		return 0
+	}
+
+	if isExact(left) && isExact(right) {
+		return toRat(left).Cmp(toRat(right))
+	}
+
+	a, b := toFloat(left), toFloat(right)
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+// numbersEqv is eqv? for numbers, exactness must match as well as the value
+func numbersEqv(left, right Object) bool {
+	if isExact(left) != isExact(right) {
+		return false
+	}
+
+	if isExact(left) {
+		return numberCompare(left, right) == 0
+	}
+
+	return toFloat(left) == toFloat(right)
+}
+
+// normalizeBig demotes a big.Int to an Integer when it fits in an int64
+func normalizeBig(value *big.Int) Object {
+	if value.IsInt64() {
+		return &Integer{Value: value.Int64()}
+	}
+
+	return &BigInteger{Value: value}
+}
+
+// normalizeRat demotes a big.Rat with a denominator of 1 to an integer
+func normalizeRat(value *big.Rat) Object {
+	if value.IsInt() {
+		return normalizeBig(new(big.Int).Set(value.Num()))
+	}
+
+	return &Rational{Value: value}
+}
+
+func isNumber(obj Object) bool {
+	switch obj.(type) {
+	case *Integer, *BigInteger, *Rational, *Float:
+		return true
+	}
+
+	return false
+}
+
+func isExact(obj Object) bool {
+	switch obj.(type) {
+	case *Integer, *BigInteger, *Rational:
+		return true
+	}
+
+	return false
+}
+
+func isExactInteger(obj Object) bool {
+	switch obj.(type) {
+	case *Integer, *BigInteger:
+		return true
+	}
+
+	return false
+}
+
+// isInteger is true for exact integers and floats without a fraction
+func isInteger(obj Object) bool {
+	if f, ok := obj.(*Float); ok {
+		return !math.IsInf(f.Value, 0) && f.Value == math.Trunc(f.Value)
+	}
+
+	return isExactInteger(obj)
+}
+
+func toFloat(obj Object) float64 {
+	switch num := obj.(type) {
+	case *Integer:
+		return float64(num.Value)
+	case *BigInteger:
+		f, _ := new(big.Float).SetInt(num.Value).Float64()
+		return f
+	case *Rational:
+		f, _ := num.Value.Float64()
+		return f
+	case *Float:
+		return num.Value
+	}
+
+	return 0
+}
+
+// toRat converts an exact number into a new big.Rat
+func toRat(obj Object) *big.Rat {
+	switch num := obj.(type) {
+	case *Integer:
+		return new(big.Rat).SetInt64(num.Value)
+	case *BigInteger:
+		return new(big.Rat).SetInt(num.Value)
+	case *Rational:
+		return new(big.Rat).Set(num.Value)
+	}
+
+	return new(big.Rat)
+}
+
+// toBig converts an exact integer into a new big.Int
+func toBig(obj Object) *big.Int {
+	switch num := obj.(type) {
+	case *Integer:
+		return big.NewInt(num.Value)
+	case *BigInteger:
+		return new(big.Int).Set(num.Value)
+	}
+
+	return new(big.Int)
+}
+
+// toExact converts any number into its exact equivalent
+func toExact(obj Object) Object {
+	f, ok := obj.(*Float)
+	if !ok {
+		return obj
+	}
+
+	if math.IsInf(f.Value, 0) || math.IsNaN(f.Value) {
+		return errorObject(fmt.Errorf("%s has no exact representation", f.Inspect()))
+	}
+
+	return normalizeRat(new(big.Rat).SetFloat64(f.Value))
+}
+
+// integerDivision implements floor/ and truncate/ returning the quotient and
+// remainder, the result is inexact if either argument is
+func integerDivision(floor bool, args []Object) (Object, Object, *Error) {
+	if len(args) != 2 {
+		return nil, nil, newError("Expecting two integer arguments")
+	}
+
+	for _, arg := range args {
+		if !isInteger(arg) {
+			return nil, nil, errorObject(fmt.Errorf("Unexpected %s expecting an Integer", arg.Inspect()))
+		}
+	}
+
+	n, d := toBig(toExact(args[0])), toBig(toExact(args[1]))
+	if d.Sign() == 0 {
+		return nil, nil, newError("Division by zero")
+	}
+
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if floor && r.Sign() != 0 && r.Sign() != d.Sign() {
+		q.Sub(q, big.NewInt(1))
+		r.Add(r, d)
+	}
+
+	quotient, remainder := normalizeBig(q), normalizeBig(r)
+	if !isExact(args[0]) || !isExact(args[1]) {
+		return &Float{Value: toFloat(quotient)}, &Float{Value: toFloat(remainder)}, nil
+	}
+
+	return quotient, remainder, nil
+}
+
+// parseNumber parses a numeric literal with optional #e #i #x #b #o #d
+// prefixes, it returns nil when token is not a number
+func parseNumber(token string) Object {
+	radix := 10
+	exactness := byte(0)
+
+	for len(token) > 1 && token[0] == '#' {
+		switch token[1] {
+		case 'x', 'X':
+			radix = 16
+		case 'b', 'B':
+			radix = 2
+		case 'o', 'O':
+			radix = 8
+		case 'd', 'D':
+			radix = 10
+		case 'e', 'E', 'i', 'I':
+			exactness = token[1] | 0x20
+		default:
+			return nil
+		}
+
+		token = token[2:]
+	}
+
+	// exact decimals are parsed directly so exponents beyond float64 range
+	// such as #e1e400 still read
+	if exactness == 'e' && radix == 10 && hasDigit(token, radix) && !strings.ContainsAny(token, "_bBoOxXpP") {
+		if rat, ok := new(big.Rat).SetString(token); ok {
+			return normalizeRat(rat)
+		}
+	}
+
+	num := parseReal(token, radix)
+	if num == nil {
+		return nil
+	}
+
+	switch exactness {
+	case 'e':
+		return toExact(num)
+	case 'i':
+		return &Float{Value: toFloat(num)}
+	}
+
+	return num
+}
+
+func parseReal(token string, radix int) Object {
+	switch strings.ToLower(token) {
+	case "+inf.0":
+		return &Float{Value: math.Inf(1)}
+	case "-inf.0":
+		return &Float{Value: math.Inf(-1)}
+	case "+nan.0", "-nan.0":
+		return &Float{Value: math.NaN()}
+	}
+
+	if !hasDigit(token, radix) {
+		return nil
+	}
+
+	if parts := strings.SplitN(token, "/", 2); len(parts) == 2 {
+		num, ok := new(big.Int).SetString(parts[0], radix)
+		if !ok {
+			return nil
+		}
+
+		den, ok := new(big.Int).SetString(parts[1], radix)
+		if !ok || den.Sign() <= 0 || strings.ContainsAny(parts[1], "+-") {
+			return nil
+		}
+
+		return normalizeRat(new(big.Rat).SetFrac(num, den))
+	}
+
+	if i, err := strconv.ParseInt(token, radix, 64); err == nil {
+		return &Integer{Value: i}
+	}
+
+	if i, ok := new(big.Int).SetString(token, radix); ok {
+		return normalizeBig(i)
+	}
+
+	if radix != 10 || strings.ContainsAny(token, "_xXpP") {
+		return nil
+	}
+
+	if f, err := strconv.ParseFloat(token, 64); err == nil {
+		return &Float{Value: f}
+	}
+
+	return nil
+}
+
+// hasDigit guards against strconv accepting words such as inf or nan
+func hasDigit(token string, radix int) bool {
+	start := 0
+	if len(token) > 0 && (token[0] == '+' || token[0] == '-') {
+		start = 1
+	}
+
+	if start < len(token) && token[start] == '.' {
+		start++
+	}
+
+	if start >= len(token) {
+		return false
+	}
+
+	_, err := strconv.ParseUint(token[start:start+1], radix, 8)
+	return err == nil
+}
+
+func notANumber(obj Object) *Error {
+	return errorObject(fmt.Errorf("Unexpected %s expecting a number", obj.Inspect()))
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"container/list"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 // BuiltinFunction type
@@ -33,7 +39,56 @@ type Float struct {
 
 // Inspect object
 func (f *Float) Inspect() string {
-	return fmt.Sprintf("%f", f.Value)
+	switch {
+	case math.IsInf(f.Value, 1):
+		return "+inf.0"
+	case math.IsInf(f.Value, -1):
+		return "-inf.0"
+	case math.IsNaN(f.Value):
+		return "+nan.0"
+	}
+
+	str := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if !strings.ContainsAny(str, ".e") {
+		str += ".0"
+	}
+
+	return str
+}
+
+// BigInteger is an exact integer too large for an Integer
+type BigInteger struct {
+	Value *big.Int
+}
+
+// Inspect object
+func (b *BigInteger) Inspect() string {
+	return b.Value.String()
+}
+
+// Rational is an exact fraction
+type Rational struct {
+	Value *big.Rat
+}
+
+// Inspect object
+func (r *Rational) Inspect() string {
+	return r.Value.String()
+}
+
+// Values holds multiple return values
+type Values struct {
+	Value []Object
+}
+
+// Inspect the values
+func (v *Values) Inspect() string {
+	strs := []string{}
+	for _, obj := range v.Value {
+		strs = append(strs, obj.Inspect())
+	}
+
+	return strings.Join(strs, " ")
 }
 
 // Builtin function
@@ -57,32 +112,58 @@ func (builtin *ScopedBuiltin) Inspect() string {
 	return "<#procedure>"
 }
 
-// Lambda represents a lambda!
+// Lambda represents a lambda! Body is the list of body expressions and Rest
+// collects any arguments after Parameters
 type Lambda struct {
+	Name       string
 	Parameters []*Identifier
+	Rest       *Identifier
 	Body       Object
 	Env        *Environment
-	Data       bool
 }
 
 // Inspect the builtin
 func (l *Lambda) Inspect() string {
-	if l.Data {
-		str := "(lambda ("
-		args := []string{}
-		for _, arg := range l.Parameters {
-			args = append(args, arg.Inspect())
-		}
-
-		str += strings.Join(args, " ") + ") "
-		str += l.Body.Inspect() + ")"
+	return "<#procedure>"
+}
 
-		return str
-	}
+// CaseLambda calls the first clause accepting the number of arguments
+type CaseLambda struct {
+	Name    string
+	Clauses []*Lambda
+}
 
+// Inspect the case lambda
+func (c *CaseLambda) Inspect() string {
 	return "<#procedure>"
 }
 
+// Continuation is created by CALL/CC
+type Continuation struct {
+	winds    *wind
+	handlers *handler
+	active   bool
+}
+
+// Inspect the continuation
+func (k *Continuation) Inspect() string {
+	return "<#continuation>"
+}
+
+// Macro is a SYNTAX-RULES transformer, Env is where it was defined
+type Macro struct {
+	Name     string
+	Literals []string
+	Ellipsis string
+	Rules    [][2]Object
+	Env      *Environment
+}
+
+// Inspect the macro
+func (m *Macro) Inspect() string {
+	return "<#macro " + m.Name + ">"
+}
+
 // Boolean representation
 type Boolean struct {
 	Value bool
@@ -97,19 +178,33 @@ func (b *Boolean) Inspect() string {
 	return "#F"
 }
 
+// Unspecified is the type of UNSPECIFIED
+type Unspecified struct{}
+
+// Inspect the unspecified value
+func (u *Unspecified) Inspect() string {
+	return "#<unspecified>"
+}
+
 // String represents a string in scheme
 type String struct {
 	Value string
 }
 
-// Inspect the string
+// Inspect the string with escapes
 func (s *String) Inspect() string {
-	return "\"" + s.Value + "\""
+	return writeString(s.Value)
 }
 
-// Error wraps a go error
+// Error wraps a go error, Condition is the object handed to exception
+// handlers when it is raised
 type Error struct {
-	Value error
+	Value       error
+	Condition   Object
+	Backtrace   []callFrame
+	continuable bool
+	target      *handler
+	delivered   bool
 }
 
 // Inspect the error
@@ -117,48 +212,146 @@ func (e *Error) Inspect() string {
 	return e.Value.Error()
 }
 
+// ErrorObject is the condition created by ERROR and internal errors
+type ErrorObject struct {
+	Kind      string
+	Message   string
+	Irritants Object
+	Backtrace []callFrame
+}
+
+// Inspect the error object
+func (e *ErrorObject) Inspect() string {
+	return "<#error " + e.Message + ">"
+}
+
+// RecordType is created by DEFINE-RECORD-TYPE
+type RecordType struct {
+	Name   string
+	Fields []string
+}
+
+// Inspect the record type
+func (t *RecordType) Inspect() string {
+	return "#<record-type " + t.Name + ">"
+}
+
+// Record is an instance of a RecordType
+type Record struct {
+	Type   *RecordType
+	Values []Object
+}
+
+// Inspect the record and its fields
+func (r *Record) Inspect() string {
+	strs := []string{"#<record", r.Type.Name}
+
+	for idx, field := range r.Type.Fields {
+		strs = append(strs, strings.ToLower(field)+":", r.Values[idx].Inspect())
+	}
+
+	return strings.Join(strs, " ") + ">"
+}
+
+// Bytevector is a fixed length array of bytes, literals read from source
+// are constant
+type Bytevector struct {
+	Value    []byte
+	Constant bool
+}
+
+// Inspect the bytevector as a #u8 literal
+func (b *Bytevector) Inspect() string {
+	strs := []string{}
+	for _, value := range b.Value {
+		strs = append(strs, strconv.Itoa(int(value)))
+	}
+
+	return "#u8(" + strings.Join(strs, " ") + ")"
+}
+
+// Port reads from or writes to a stream, binary ports handle bytes rather
+// than characters
+type Port struct {
+	Name   string
+	reader *Reader
+	writer io.Writer
+	closer io.Closer
+	open   bool
+	binary bool
+}
+
+// Inspect the port
+func (p *Port) Inspect() string {
+	kind := ""
+	if p.binary {
+		kind = "binary-"
+	}
+
+	if p.reader != nil {
+		return "<#" + kind + "input-port " + p.Name + ">"
+	}
+
+	return "<#" + kind + "output-port " + p.Name + ">"
+}
+
+// EndOfFile is the type of EOFOBJECT
+type EndOfFile struct{}
+
+// Inspect the eof object
+func (e *EndOfFile) Inspect() string {
+	return "#<eof>"
+}
+
+// HashTable associates keys with values using a comparator
+type HashTable struct {
+	comparator *comparator
+	buckets    map[string][]*hashEntry
+	entries    *list.List
+}
+
+// Inspect the hash table
+func (t *HashTable) Inspect() string {
+	return fmt.Sprintf("<#hash-table %d>", t.entries.Len())
+}
+
 // Pair represents a pair of cons cells
 type Pair struct {
-	Car Object
-	Cdr Object
+	Car    Object
+	Cdr    Object
+	Pos    *Position
+	CarPos *Position
 }
 
-// Inspect the pair
+// Inspect the pair, nested and improper lists included
 func (c *Pair) Inspect() string {
-	if c.Cdr == nil && c.Car == nil {
-		return "()"
-	} else if c.Cdr == nil {
-		return "(" + c.Car.Inspect() + ")"
-	}
+	return writeDatum(c, false)
+}
 
-	switch c.Cdr.(type) {
-	case *Pair:
-		end := c.Cdr.(*Pair)
-		cdr := ""
-
-		for {
-			cdr += end.Car.Inspect()
-			cdr += " "
-			if end.Cdr != nil {
-				end = end.Cdr.(*Pair)
-			} else {
-				break
-			}
-		}
-		return "(" + c.Car.Inspect() + " " + strings.TrimSpace(cdr) + ")"
-	default:
-		return "(" + c.Car.Inspect() + " . " + c.Cdr.Inspect() + ")"
-	}
+// EmptyList is the type of NIL
+type EmptyList struct{}
+
+// Inspect the empty list
+func (e *EmptyList) Inspect() string {
+	return "()"
 }
 
-// Char representation
+// Char representation holding a unicode code point
 type Char struct {
-	Value string
+	Value rune
 }
 
-// Inspect a char
+// Inspect a char, named characters and unprintable ones by their hex value
 func (c *Char) Inspect() string {
-	return "#\\" + c.Value
+	if name, ok := charName(c.Value); ok {
+		return "#\\" + name
+	}
+
+	if !unicode.IsPrint(c.Value) {
+		return fmt.Sprintf("#\\x%x", c.Value)
+	}
+
+	return "#\\" + string(c.Value)
 }
 
 // Identifier is a symbol
@@ -171,32 +364,13 @@ func (i *Identifier) Inspect() string {
 	return i.Value
 }
 
-// Vector wraps a Pair
+// Vector is a fixed length array, literals read from source are constant
 type Vector struct {
-	Value []Object
+	Value    []Object
+	Constant bool
 }
 
-// Inspect return the inspect of the Pair prepending #
+// Inspect the vector, nested and cyclic vectors included
 func (v *Vector) Inspect() string {
-	str := "#("
-
-	for _, obj := range v.Value {
-		str += obj.Inspect()
-		str += " "
-	}
-
-	str = strings.TrimSpace(str)
-	str += ")"
-
-	return str
-}
-
-// Data object
-type Data struct {
-	Value string
-}
-
-// Inspect the data
-func (d *Data) Inspect() string {
-	return d.Value
+	return writeDatum(v, false)
 }
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// stdin is shared by the REPL and the standard input port
+var stdin = bufio.NewReader(os.Stdin)
+
+var currentInput = newInputPort("stdin", NewBufferedReader(stdin, "stdin"), nil)
+var currentOutput = newOutputPort("stdout", os.Stdout, nil)
+var currentError = newOutputPort("stderr", os.Stderr, nil)
+
+// newInputPort creates a port reading with reader, closer is called when
+// the port is closed
+func newInputPort(name string, reader *Reader, closer io.Closer) *Port {
+	return &Port{Name: name, reader: reader, closer: closer, open: true}
+}
+
+// newOutputPort creates a port writing to writer, closer is called when the
+// port is closed
+func newOutputPort(name string, writer io.Writer, closer io.Closer) *Port {
+	return &Port{Name: name, writer: writer, closer: closer, open: true}
+}
+
+// newInputString creates a port reading str
+func newInputString(str string) *Port {
+	return newInputPort("string", NewNamedReader(str, "string"), nil)
+}
+
+// newOutputString creates a port collecting what is written in memory
+func newOutputString() *Port {
+	return newOutputPort("string", &strings.Builder{}, nil)
+}
+
+// outputString returns what was written to a string port
+func (p *Port) outputString(name string) Object {
+	buffer, ok := p.writer.(*strings.Builder)
+	if !ok {
+		return errorObject(fmt.Errorf("%s expecting an output string port found %s", name, p.Inspect()))
+	}
+
+	return &String{Value: buffer.String()}
+}
+
+// withCurrentPort calls thunk with current set to port, it is restored
+// however thunk exits
+func withCurrentPort(current **Port, port *Port, thunk Object, name string, env *Environment) Object {
+	saved := *current
+	*current = port
+	defer func() { *current = saved }()
+
+	return signalFresh(applyProcedure(thunk, name, []Object{}, env), env)
+}
+
+// flush buffered output to the stream
+func (p *Port) flush() *Error {
+	if buffered, ok := p.writer.(*bufio.Writer); ok {
+		if err := buffered.Flush(); err != nil {
+			return errorObject(err)
+		}
+	}
+
+	return nil
+}
+
+// close the port and its stream
+func (p *Port) close() *Error {
+	if !p.open {
+		return nil
+	}
+
+	p.open = false
+	delete(outputFiles, p)
+	if err := p.flush(); err != nil {
+		return err
+	}
+
+	if p.closer != nil {
+		if err := p.closer.Close(); err != nil {
+			return errorObject(err)
+		}
+	}
+
+	return nil
+}
+
+// write str to an output port
+func (p *Port) write(str string) Object {
+	if _, err := io.WriteString(p.writer, str); err != nil {
+		return errorObject(err)
+	}
+
+	return UNSPECIFIED
+}
+
+// readChar reads a character or returns the eof object
+func (p *Port) readChar() Object {
+	char, err := p.reader.currentRune()
+	if err != nil {
+		return p.eof(err)
+	}
+
+	return &Char{Value: char}
+}
+
+// peekChar returns the next character without consuming it
+func (p *Port) peekChar() Object {
+	peeked, err := p.reader.reader.Peek(utf8.UTFMax)
+	if len(peeked) == 0 {
+		if err == io.EOF {
+			return EOFOBJECT
+		}
+
+		return errorObject(err)
+	}
+
+	char, _ := utf8.DecodeRune(peeked)
+	return &Char{Value: char}
+}
+
+// readLine reads up to the end of the line dropping the line ending
+func (p *Port) readLine() Object {
+	line := strings.Builder{}
+
+	for {
+		char, err := p.reader.currentRune()
+		if err != nil {
+			if err.Value == io.EOF && line.Len() > 0 {
+				break
+			}
+
+			return p.eof(err)
+		}
+
+		if char == '\n' {
+			break
+		}
+
+		line.WriteRune(char)
+	}
+
+	return &String{Value: strings.TrimSuffix(line.String(), "\r")}
+}
+
+// readString reads up to count characters
+func (p *Port) readString(count int) Object {
+	str := strings.Builder{}
+
+	for idx := 0; idx < count; idx++ {
+		char, err := p.reader.currentRune()
+		if err != nil {
+			if err.Value == io.EOF && idx > 0 {
+				break
+			}
+
+			return p.eof(err)
+		}
+
+		str.WriteRune(char)
+	}
+
+	return &String{Value: str.String()}
+}
+
+// readDatum reads the next datum, input ending inside a datum is a read error
+func (p *Port) readDatum() Object {
+	if !p.reader.skipAtmosphere() {
+		return EOFOBJECT
+	}
+
+	obj := p.reader.Read()
+	if err, ok := obj.(*Error); ok && err.Value == io.EOF {
+		return readError(fmt.Sprintf("Unexpected end of input in %s", p.Name))
+	}
+
+	return obj
+}
+
+// charReady checks a character can be read without blocking
+func (p *Port) charReady() bool {
+	if p != currentInput || p.reader.reader.Buffered() > 0 {
+		return true
+	}
+
+	return false
+}
+
+// eof turns the end of the stream into the eof object
+func (p *Port) eof(err *Error) Object {
+	if err.Value == io.EOF {
+		return EOFOBJECT
+	}
+
+	return err
+}
+
+// display formats obj the way DISPLAY prints it, strings and characters
+// are written without quotes or escapes
+func display(obj Object) string {
+	return writeDatum(obj, true)
+}
+
+// writeDatum formats pairs and vectors, those reachable from themselves get
+// datum labels such as #0=(1 . #0#) so cyclic data prints in finite space
+func writeDatum(obj Object, display bool) string {
+	p := &printer{display: display, state: map[Object]int{}, labels: map[Object]int{}}
+	p.findCycles(obj)
+
+	return p.write(obj)
+}
+
+// states of a pair or vector while looking for cycles
+const (
+	visiting = iota + 1
+	visited
+	cyclic
+)
+
+// printer writes data with datum labels for cycles
+type printer struct {
+	display bool
+	state   map[Object]int
+	labels  map[Object]int
+}
+
+// findCycles marks the pairs and vectors that can reach themselves, the cdr
+// of a list is followed in a loop so long lists do not deepen the Go stack
+func (p *printer) findCycles(obj Object) {
+	spine := []Object{}
+
+	for {
+		switch node := obj.(type) {
+		case *Pair:
+			if p.seen(node) {
+				break
+			}
+
+			spine = append(spine, node)
+			p.findCycles(node.Car)
+			obj = node.Cdr
+			continue
+		case *Vector:
+			if p.seen(node) {
+				break
+			}
+
+			for _, item := range node.Value {
+				p.findCycles(item)
+			}
+
+			p.leave(node)
+		}
+
+		break
+	}
+
+	for _, node := range spine {
+		p.leave(node)
+	}
+}
+
+// seen reports whether node was already visited, marking it cyclic when it
+// is one of the nodes currently being visited
+func (p *printer) seen(node Object) bool {
+	switch p.state[node] {
+	case visiting, cyclic:
+		p.state[node] = cyclic
+		return true
+	case visited:
+		return true
+	}
+
+	p.state[node] = visiting
+	return false
+}
+
+// leave finishes visiting node
+func (p *printer) leave(node Object) {
+	if p.state[node] == visiting {
+		p.state[node] = visited
+	}
+}
+
+// write formats obj using a label for every cyclic node
+func (p *printer) write(obj Object) string {
+	prefix := ""
+	if p.state[obj] == cyclic {
+		if label, ok := p.labels[obj]; ok {
+			return fmt.Sprintf("#%d#", label)
+		}
+
+		p.labels[obj] = len(p.labels)
+		prefix = fmt.Sprintf("#%d=", p.labels[obj])
+	}
+
+	switch node := obj.(type) {
+	case *String:
+		if p.display {
+			return node.Value
+		}
+	case *Char:
+		if p.display {
+			return string(node.Value)
+		}
+	case *Pair:
+		strs := []string{p.write(node.Car)}
+
+		rest := node.Cdr
+		for {
+			pair, ok := rest.(*Pair)
+			if !ok || p.state[pair] == cyclic {
+				break
+			}
+
+			strs = append(strs, p.write(pair.Car))
+			rest = pair.Cdr
+		}
+
+		if rest != NIL {
+			strs = append(strs, ".", p.write(rest))
+		}
+
+		return prefix + "(" + strings.Join(strs, " ") + ")"
+	case *Vector:
+		strs := []string{}
+		for _, item := range node.Value {
+			strs = append(strs, p.write(item))
+		}
+
+		return prefix + "#(" + strings.Join(strs, " ") + ")"
+	}
+
+	return obj.Inspect()
+}
+
+// expectPort reads the optional port argument at index defaulting to the
+// current input or output port, binary selects bytes rather than characters
+func expectPort(name string, args []Object, index int, output bool, binary bool) (*Port, *Error) {
+	if len(args) > index+1 {
+		return nil, errorObject(fmt.Errorf("%s expecting at most %d argument(s) got %d", name, index+1, len(args)))
+	}
+
+	var port *Port
+	if len(args) <= index {
+		port = currentInput
+		if output {
+			port = currentOutput
+		}
+	} else {
+		port, _ = args[index].(*Port)
+	}
+
+	if port == nil || (output && port.writer == nil) || (!output && port.reader == nil) || port.binary != binary {
+		kind := "a textual"
+		if binary {
+			kind = "a binary"
+		}
+
+		direction := "input"
+		if output {
+			direction = "output"
+		}
+
+		var found Object = port
+		if len(args) > index {
+			found = args[index]
+		}
+
+		return nil, errorObject(fmt.Errorf("%s expecting %s %s port found %s", name, kind, direction, found.Inspect()))
+	}
+
+	if !port.open {
+		return nil, errorObject(fmt.Errorf("%s expecting an open port found %s", name, port.Inspect()))
+	}
+
+	return port, nil
+}
+
+// closePort creates a procedure closing ports accepted by kind
+func closePort(name string, kind func(*Port) bool) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs(name, args, 1); err != nil {
+				return err
+			}
+
+			port, ok := args[0].(*Port)
+			if !ok || (kind != nil && !kind(port)) {
+				return errorObject(fmt.Errorf("%s expecting a port found %s", name, args[0].Inspect()))
+			}
+
+			if err := port.close(); err != nil {
+				return err
+			}
+
+			return UNSPECIFIED
+		},
+	}
+}
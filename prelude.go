@@ -0,0 +1,57 @@
+package main
+
+// prelude defines the derived syntax on top of the core special forms
+const prelude = `
+(define-syntax let
+  (syntax-rules ()
+    ((_ ((name val) ...) body1 body2 ...)
+     ((lambda (name ...) body1 body2 ...) val ...))
+    ((_ tag ((name val) ...) body1 body2 ...)
+     ((letrec ((tag (lambda (name ...) body1 body2 ...))) tag) val ...))))
+
+(define-syntax let*
+  (syntax-rules ()
+    ((_ () body1 body2 ...) (let () body1 body2 ...))
+    ((_ ((name1 val1) (name2 val2) ...) body1 body2 ...)
+     (let ((name1 val1)) (let* ((name2 val2) ...) body1 body2 ...)))))
+
+(define-syntax letrec*
+  (syntax-rules ()
+    ((_ ((var init) ...) body1 body2 ...)
+     (let () (define var init) ... (let () body1 body2 ...)))))
+
+(define-syntax letrec
+  (syntax-rules ()
+    ((_ ((var init) ...) body1 body2 ...)
+     (letrec* ((var init) ...) body1 body2 ...))))
+
+(define-syntax do
+  (syntax-rules ()
+    ((_ ((var init step ...) ...) (test expr ...) command ...)
+     (let loop ((var init) ...)
+       (if test
+           (begin (if #f #f) expr ...)
+           (begin command ... (loop (do "step" var step ...) ...)))))
+    ((_ "step" x) x)
+    ((_ "step" x y) y)))
+
+(define-syntax and
+  (syntax-rules ()
+    ((_) #t)
+    ((_ test) test)
+    ((_ test1 test2 ...) (if test1 (and test2 ...) #f))))
+
+(define-syntax or
+  (syntax-rules ()
+    ((_) #f)
+    ((_ test) test)
+    ((_ test1 test2 ...) (let ((x test1)) (if x x (or test2 ...))))))
+
+(define-syntax when
+  (syntax-rules ()
+    ((_ test result1 result2 ...) (if test (begin result1 result2 ...)))))
+
+(define-syntax unless
+  (syntax-rules ()
+    ((_ test result1 result2 ...) (if test (if #f #f) (begin result1 result2 ...)))))
+`
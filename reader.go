@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // TRUE is the only true value
@@ -14,30 +15,75 @@ var TRUE = &Boolean{Value: true}
 // FALSE is the only false value
 var FALSE = &Boolean{Value: false}
 
+// UNSPECIFIED is the value of expressions that have no useful result
+var UNSPECIFIED = &Unspecified{}
+
+// NIL is the only empty list
+var NIL = &EmptyList{}
+
+// symbols holds every interned identifier so symbols with the same name are
+// the same object
+var symbols = map[string]*Identifier{}
+
+// intern returns the unique identifier named name
+func intern(name string) *Identifier {
+	if ident, ok := symbols[name]; ok {
+		return ident
+	}
+
+	ident := &Identifier{Value: name}
+	symbols[name] = ident
+
+	return ident
+}
+
+// EOFOBJECT is the only end of file object
+var EOFOBJECT = &EndOfFile{}
+
 // EOF check for end of file
 const EOF = "EOF"
 
-// Reader wraps a bufio.Reader for us
+// Reader wraps a bufio.Reader for us and tracks the position in the source
 type Reader struct {
 	reader *bufio.Reader
+	pos    Position
+	last   Position
+	datum  Position
 }
 
 // NewReader takes in a string and returns a new Reader
 func NewReader(input string) *Reader {
-	return &Reader{bufio.NewReader(strings.NewReader(input))}
+	return NewNamedReader(input, "")
+}
+
+// NewNamedReader returns a new Reader whose positions name file
+func NewNamedReader(input string, file string) *Reader {
+	return NewBufferedReader(bufio.NewReader(strings.NewReader(input)), file)
+}
+
+// NewBufferedReader returns a Reader over an already buffered stream
+func NewBufferedReader(buffered *bufio.Reader, file string) *Reader {
+	return &Reader{reader: buffered, pos: Position{File: file, Line: 1, Column: 1}}
+}
+
+// Position returns where the next byte will be read from
+func (r *Reader) Position() Position {
+	return r.pos
 }
 
 // ReadAll will read until it encounters an error
 func (r *Reader) ReadAll() []Object {
 	var program []Object
 
-	for {
+	for r.skipAtmosphere() {
 		obj := r.Read()
 
 		if isError(obj) {
 			err := obj.(*Error)
 			if err.Value.Error() != EOF {
 				program = append(program, err)
+			} else {
+				program = append(program, readError("Unexpected end of input"))
 			}
 
 			break
@@ -51,11 +97,16 @@ func (r *Reader) ReadAll() []Object {
 
 // Read will parse and return an object on each call
 func (r *Reader) Read() Object {
+	start := r.pos
 	char, err := r.currentByte()
 	if err != nil {
 		return err
 	}
 
+	if !isWS(char) && char != ';' {
+		r.datum = start
+	}
+
 	switch char {
 	case '#':
 		val, err := r.peek()
@@ -73,11 +124,7 @@ func (r *Reader) Read() Object {
 			return FALSE
 		} else if peekChar == "\\" {
 			r.skip()
-			cur, err := r.currentByte()
-			if err != nil {
-				return err
-			}
-			return &Char{Value: string(cur)}
+			return r.readChar()
 		} else if peekChar == "(" {
 			r.skip()
 			values := []Object{}
@@ -93,80 +140,69 @@ func (r *Reader) Read() Object {
 					break
 				}
 
-				values = append(values, r.Read())
-			}
-
-			return &Vector{Value: values}
-		}
-
-		return newError(fmt.Sprintf("Expecting one of F or T or \\ found %s instead.", peekChar))
-	case '"':
-		str := bytes.Buffer{}
-
-		for {
-			cur, err := r.currentByte()
-
-			if err != nil {
-				return err
-			}
-
-			switch cur {
-			case '"':
-				return &String{Value: ""}
-			case '\n':
-				str.WriteString("\n")
-			default:
-				str.WriteByte(cur)
-			}
-
-			peekChar, err := r.preserveWsPeek(true)
-
-			if err != nil {
-				if err.Inspect() == EOF {
-					return newError("Missing closing \"")
+				obj := r.Read()
+				if isError(obj) {
+					return obj
 				}
 
-				return err
+				values = append(values, obj)
 			}
 
-			if peekChar == '"' {
-				break
+			return &Vector{Value: values, Constant: true}
+		} else if peekChar == "!" {
+			// #! starts a shebang line or a directive, neither has a value
+			r.PairumeComment()
+			return r.Read()
+		} else if peekChar == "U" {
+			return r.readBytevector()
+		} else if strings.Contains("EIXBOD", peekChar) {
+			num := r.identOrDigit(char)
+			if ident, ok := num.(*Identifier); ok {
+				return readError(fmt.Sprintf("Invalid number literal %s", ident.Value))
 			}
-		}
 
-		cur, err := r.currentByte()
-
-		if err != nil {
-			return err
+			return num
 		}
 
-		if cur != '"' {
-			return newError("Missing closing \"")
+		return readError(fmt.Sprintf("Expecting one of F or T or \\ found %s instead.", peekChar))
+	case '"':
+		return r.readString()
+	case '\'':
+		cdr := r.Read()
+
+		if isError(cdr) {
+			return cdr
 		}
 
-		return &String{Value: str.String()}
-	case '\'':
+		return &Pair{Car: intern("QUOTE"), Cdr: &Pair{Car: cdr, Cdr: NIL}, Pos: &start}
+	case '`':
 		cdr := r.Read()
 
 		if isError(cdr) {
 			return cdr
 		}
 
-		switch obj := cdr.(type) {
-		case *Lambda:
-			obj.Data = true
-			return &Pair{Car: &Identifier{Value: "QUOTE"}, Cdr: &Pair{Car: &Data{Value: obj.Inspect()}}}
-		default:
-			return &Pair{Car: &Identifier{Value: "QUOTE"}, Cdr: &Pair{Car: &Data{Value: cdr.Inspect()}}}
+		return &Pair{Car: intern("QUASIQUOTE"), Cdr: &Pair{Car: cdr, Cdr: NIL}, Pos: &start}
+	case ',':
+		name := "UNQUOTE"
+
+		peekChar, err := r.preserveWsPeek(true)
+		if err != nil {
+			return err
 		}
-	case '`':
+
+		if peekChar == '@' {
+			r.skip()
+			name = "UNQUOTE-SPLICING"
+		}
+
 		cdr := r.Read()
 
 		if isError(cdr) {
 			return cdr
 		}
 
-		return &Pair{Car: &Identifier{Value: "QUASIQUOTE"}, Cdr: &Pair{Car: cdr}}
+		return &Pair{Car: intern(name), Cdr: &Pair{Car: cdr, Cdr: NIL}, Pos: &start}
 	case '(':
 		peekChar, err := r.peek()
 
@@ -176,7 +212,7 @@ func (r *Reader) Read() Object {
 
 		if peekChar == ')' {
 			r.skip()
-			return &Pair{}
+			return NIL
 		}
 
 		obj := r.Read()
@@ -184,18 +220,8 @@ func (r *Reader) Read() Object {
 			return obj
 		}
 
-		switch node := obj.(type) {
-		case *Identifier:
-			if node.Value == "LAMBDA" {
-				return r.readLambda()
-			} else if node.Value == "LET" {
-				return r.expandLet()
-			} else if node.Value == "DEFINE" {
-				return r.expandDefine(node)
-			}
-		}
-
-		list := &Pair{Car: obj}
+		list := &Pair{Car: obj, Cdr: NIL, Pos: &start}
+		r.locateAtom(list)
 		lastPair := list
 
 		for {
@@ -210,12 +236,8 @@ func (r *Reader) Read() Object {
 				break
 			}
 
-			cur, err := r.currentByte()
-			if err != nil {
-				return err
-			}
-
-			if cur == '.' {
+			if r.atDot() {
+				r.skip()
 				obj = r.Read()
 
 				if isError(obj) {
@@ -224,17 +246,14 @@ func (r *Reader) Read() Object {
 
 				lastPair.Cdr = obj
 			} else {
-				err := r.unreadByte()
-				if err != nil {
-					return err
-				}
 				obj = r.Read()
 				if isError(obj) {
 					return obj
 				}
 
-				lastPair.Cdr = &Pair{Car: obj}
+				lastPair.Cdr = &Pair{Car: obj, Cdr: NIL}
 				lastPair = lastPair.Cdr.(*Pair)
+				r.locateAtom(lastPair)
 			}
 		}
 
@@ -254,34 +273,18 @@ func (r *Reader) Read() Object {
 		}
 
 		return r.Read()
-	case '+', '*', '/', '=':
-		return &Identifier{Value: string(char)}
-	case '-':
-		return r.identOrDigit(char)
-	case '<':
-		peekChar, err := r.peek()
-		if err != nil {
-			return err
-		}
-
-		if peekChar == '=' {
+	case '*', '/':
+		return intern(string(char))
+	case '=':
+		peekChar, err := r.preserveWsPeek(true)
+		if err == nil && peekChar == '>' {
 			r.skip()
-			return &Identifier{Value: "<="}
-		}
-
-		return &Identifier{Value: "<"}
-	case '>':
-		peekChar, err := r.peek()
-		if err != nil {
-			return err
+			return intern("=>")
 		}
 
-		if peekChar == '=' {
-			r.skip()
-			return &Identifier{Value: ">="}
-		}
-
-		return &Identifier{Value: ">"}
+		return intern("=")
+	case '+', '-', '<', '>':
+		return r.identOrDigit(char)
 	case ';':
 		r.PairumeComment()
 		return r.Read()
@@ -290,83 +293,13 @@ func (r *Reader) Read() Object {
 	}
 }
 
-// Expand define into something out interpreter can handle
-func (r *Reader) expandDefine(ident *Identifier) Object {
-	pair := &Pair{Car: ident}
-
-	first := r.Read()
-	switch kind := first.(type) {
-	case *Identifier:
-		value := r.Read()
-		pair.Cdr = &Pair{Car: kind, Cdr: &Pair{Car: value}}
-	case *Pair:
-		variable := car(kind)
-		params := []*Identifier{}
-
-		for {
-			param := kind.Cdr.(*Pair)
-			params = append(params, car(param).(*Identifier))
-
-			if param.Cdr != nil {
-				kind.Cdr = param.Cdr
-			} else {
-				break
-			}
-
-		}
-
-		body := r.Read()
-		lambda := &Pair{Car: &Lambda{Parameters: params, Body: body}}
-		pair.Cdr = &Pair{Car: variable, Cdr: lambda}
-	}
-
-	return pair
-}
-
-// Let expands into a lambda call
-func (r *Reader) expandLet() Object {
-	args := []string{}
-	params := []string{}
-
-	if pair, ok := r.Read().(*Pair); ok {
-		for {
-			if first, ok := car(pair).(*Pair); ok {
-				if ident, ok := car(first).(*Identifier); ok {
-					params = append(params, ident.Inspect())
-					arg := car(cdr(first))
-					args = append(args, arg.Inspect())
-				} else {
-					pair = first
-					continue
-				}
-
-				if pair.Cdr != nil {
-					pair.Car = pair.Cdr
-				} else {
-					break
-				}
-			} else {
-				return newError("expecting a proper list")
-			}
-		}
-	} else {
-		return newError("expecting a proper list")
+// locateAtom records where the car of pair was read when it is an atom,
+// pairs carry their own position
+func (r *Reader) locateAtom(pair *Pair) {
+	if _, ok := pair.Car.(*Pair); !ok {
+		pos := r.datum
+		pair.CarPos = &pos
 	}
-
-	body := r.Read()
-	peekChar, err := r.peek()
-	if err != nil {
-		return err
-	}
-
-	if peekChar == ')' {
-		r.skip()
-	}
-
-	// Expand let into a lambda call to preserve lexical scoping
-	lambda := fmt.Sprintf("((lambda (%s) %s) %s)", strings.Join(params, " "), body.Inspect(), strings.Join(args, " "))
-	reader := NewReader(lambda)
-	return reader.Read()
 }
 
 func car(obj Object) Object {
@@ -386,11 +319,32 @@ func cdr(obj Object) Object {
 }
 
 func (r *Reader) PairumeComment() {
-	peekChar, _ := r.preserveWsPeek(true)
+	peekChar, err := r.preserveWsPeek(true)
 
-	for peekChar != '\n' && peekChar != '\r' {
+	for err == nil && peekChar != '\n' && peekChar != '\r' {
 		r.skip()
-		peekChar, _ = r.preserveWsPeek(true)
+		peekChar, err = r.preserveWsPeek(true)
+	}
+}
+
+// skipAtmosphere skips whitespace and comments, it reports whether a datum
+// may follow
+func (r *Reader) skipAtmosphere() bool {
+	for {
+		peekChar, err := r.peek()
+		if err != nil {
+			return false
+		}
+
+		if peekChar == '#' {
+			if next, err := r.reader.Peek(2); err != nil || next[1] != '!' {
+				return true
+			}
+		} else if peekChar != ';' {
+			return true
+		}
+
+		r.PairumeComment()
 	}
 }
 
@@ -407,11 +361,7 @@ func (r *Reader) identOrDigit(char byte) Object {
 			return err
 		}
 
-		if isWS(char) {
-			break
-		}
-
-		if char == '(' || char == ')' {
+		if isWS(char) || char == '(' || char == ')' {
 			err := r.unreadByte()
 			if err != nil {
 				return err
@@ -423,113 +373,207 @@ func (r *Reader) identOrDigit(char byte) Object {
 		str.WriteByte(char)
 	}
 
-	i, err := strconv.ParseInt(str.String(), 0, 64)
+	if num := parseNumber(str.String()); num != nil {
+		return num
+	}
+
+	return intern(strings.ToUpper(str.String()))
+}
+
+func (r *Reader) peek() (byte, *Error) {
+	return r.preserveWsPeek(false)
+}
+
+func (r *Reader) preserveWsPeek(enable bool) (byte, *Error) {
+	bytes, err := r.reader.Peek(1)
 
 	if err != nil {
-		f, err := strconv.ParseFloat(str.String(), 64)
+		return 1, &Error{Value: err}
+	}
 
-		if err != nil {
-			return &Identifier{Value: strings.ToUpper(str.String())}
+	if !enable && isWS(bytes[0]) {
+		for isWS(bytes[0]) {
+			r.skip()
+			bytes, err = r.reader.Peek(1)
+			if err != nil {
+				return 1, errorObject(err)
+			}
 		}
+	}
+
+	return bytes[0], nil
+}
+
+func (r *Reader) currentByte() (byte, *Error) {
+	val, err := r.reader.ReadByte()
+
+	if err != nil {
+		return 1, &Error{Value: err}
+	}
 
-		return &Float{Value: f}
+	r.last = r.pos
+	if val == '\n' {
+		r.pos.Line++
+		r.pos.Column = 1
+	} else if val&0xC0 != 0x80 {
+		r.pos.Column++
 	}
 
-	return &Integer{Value: i}
+	return val, nil
 }
 
-func (r *Reader) readLambda() Object {
-	peekChar, err := r.peek()
+// currentRune decodes the next UTF-8 encoded character
+func (r *Reader) currentRune() (rune, *Error) {
+	val, _, err := r.reader.ReadRune()
+
 	if err != nil {
-		return err
+		return 0, &Error{Value: err}
 	}
 
-	if peekChar != '(' {
-		return newError("Missing opening (")
+	r.last = r.pos
+	if val == '\n' {
+		r.pos.Line++
+		r.pos.Column = 1
+	} else {
+		r.pos.Column++
 	}
 
-	r.skip()
-	curChar, err := r.currentByte()
+	return val, nil
+}
+
+// readChar reads the character literal following #\ which is either a single
+// character, a character name or x followed by a hex scalar value
+func (r *Reader) readChar() Object {
+	first, err := r.currentRune()
 	if err != nil {
 		return err
 	}
 
-	var body Object
+	name := []rune{first}
+	for {
+		peekChar, err := r.preserveWsPeek(true)
+		if err != nil || isDelimiter(peekChar) {
+			break
+		}
 
-	if curChar == ')' {
-		// No Arguments
-		body = r.Read()
+		cur, err := r.currentRune()
+		if err != nil {
+			return err
+		}
 
-		curChar, _ = r.currentByte()
+		name = append(name, cur)
+	}
 
-		if curChar != ')' {
-			return newError("missing closing )")
-		}
+	if len(name) == 1 {
+		return &Char{Value: first}
+	}
+
+	if value, ok := charByName(string(name)); ok {
+		return &Char{Value: value}
+	}
 
-		return &Lambda{Body: body}
+	if first == 'x' || first == 'X' {
+		if value, err := strconv.ParseInt(string(name[1:]), 16, 32); err == nil && utf8.ValidRune(rune(value)) {
+			return &Char{Value: rune(value)}
+		}
 	}
 
-	var arguments []*Identifier
+	return readError(fmt.Sprintf("Unknown character name %s", string(name)))
+}
 
-	r.unreadByte() // Go back one or we skip the first identifier
+// readString reads the rest of a string literal decoding its escapes
+func (r *Reader) readString() Object {
+	str := bytes.Buffer{}
 
 	for {
-		peekChar, err := r.peek()
+		cur, err := r.currentByte()
 		if err != nil {
+			if err.Inspect() == EOF {
+				return readError("Missing closing \"")
+			}
+
 			return err
 		}
 
-		if peekChar == ')' {
-			break
+		if cur == '"' {
+			return &String{Value: str.String()}
 		}
 
-		arg := r.Read()
-		arguments = append(arguments, arg.(*Identifier))
-	}
+		if cur != '\\' {
+			str.WriteByte(cur)
+			continue
+		}
 
-	r.skip() // Skip closing )
-	body = r.Read()
-	r.skip() // Skip closing )
+		escaped, err := r.currentByte()
+		if err != nil {
+			return readError("Missing closing \"")
+		}
 
-	if isError(body) {
-		return body
-	}
+		switch escaped {
+		case 'n':
+			str.WriteByte('\n')
+		case 't':
+			str.WriteByte('\t')
+		case 'r':
+			str.WriteByte('\r')
+		case 'a':
+			str.WriteByte(7)
+		case 'b':
+			str.WriteByte(8)
+		case '"', '\\', '|':
+			str.WriteByte(escaped)
+		case 'x', 'X':
+			hex := bytes.Buffer{}
+			for {
+				digit, err := r.currentByte()
+				if err != nil {
+					return readError("Missing closing \"")
+				}
 
-	return &Lambda{Body: body, Parameters: arguments}
-}
+				if digit == ';' {
+					break
+				}
 
-func (r *Reader) peek() (byte, *Error) {
-	return r.preserveWsPeek(false)
-}
+				hex.WriteByte(digit)
+			}
 
-func (r *Reader) preserveWsPeek(enable bool) (byte, *Error) {
-	bytes, err := r.reader.Peek(1)
+			value, parseErr := strconv.ParseInt(hex.String(), 16, 32)
+			if parseErr != nil || !utf8.ValidRune(rune(value)) {
+				return readError(fmt.Sprintf("Invalid string escape \\x%s;", hex.String()))
+			}
 
-	if err != nil {
-		return 1, &Error{Value: err}
+			str.WriteRune(rune(value))
+		case ' ', '\t', '\n', '\r':
+			r.skipLineContinuation(escaped)
+		default:
+			return readError(fmt.Sprintf("Unknown string escape \\%c", escaped))
+		}
 	}
+}
 
-	if !enable && isWS(bytes[0]) {
-		for isWS(bytes[0]) {
-			r.skip()
-			bytes, err = r.reader.Peek(1)
-			if err != nil {
-				return 1, errorObject(err)
-			}
+// skipLineContinuation skips the whitespace around the line ending of a \
+// at the end of a line inside a string
+func (r *Reader) skipLineContinuation(escaped byte) {
+	newline := escaped == '\n'
+
+	for {
+		peekChar, err := r.preserveWsPeek(true)
+		if err != nil {
+			return
 		}
-	}
 
-	return bytes[0], nil
-}
+		if peekChar == '\n' {
+			if newline {
+				return
+			}
 
-func (r *Reader) currentByte() (byte, *Error) {
-	val, err := r.reader.ReadByte()
+			newline = true
+		} else if peekChar != ' ' && peekChar != '\t' && peekChar != '\r' {
+			return
+		}
 
-	if err != nil {
-		return 1, &Error{Value: err}
+		r.skip()
 	}
-
-	return val, nil
 }
 
 func (r *Reader) unreadByte() *Error {
@@ -538,11 +582,29 @@ func (r *Reader) unreadByte() *Error {
 	if err != nil {
 		return &Error{Value: err}
 	}
+
+	r.pos = r.last
 	return nil
 }
 
 func (r *Reader) skip() {
-	r.reader.Discard(1)
+	r.currentByte()
+}
+
+// atDot checks the next token is a lone . rather than an identifier such
+// as ...
+func (r *Reader) atDot() bool {
+	bytes, err := r.reader.Peek(2)
+	if err != nil {
+		return false
+	}
+
+	return bytes[0] == '.' && (isWS(bytes[1]) || bytes[1] == '(' || bytes[1] == ')')
+}
+
+// isDelimiter checks char ends an identifier or character name
+func isDelimiter(char byte) bool {
+	return isWS(char) || char == '(' || char == ')' || char == '"' || char == ';'
 }
 
 func isWS(char byte) bool {
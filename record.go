@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// evalDefineRecordType defines the record type, constructor, predicate and
+// field procedures of
+// (DEFINE-RECORD-TYPE name (constructor field...) predicate (field accessor [modifier])...)
+func evalDefineRecordType(node *Pair, env *Environment) (Object, *Environment) {
+	malformed := newError("Malformed DEFINE-RECORD-TYPE expecting (DEFINE-RECORD-TYPE name (constructor field...) predicate (field accessor [modifier])...)")
+
+	args, err := listToSlice(node.Cdr)
+	if err != nil {
+		return err, nil
+	}
+
+	if len(args) < 3 {
+		return malformed, nil
+	}
+
+	typeName, ok := args[0].(*Identifier)
+	if !ok {
+		return malformed, nil
+	}
+
+	recordType := &RecordType{Name: strings.ToLower(strings.Trim(typeName.Value, "<>"))}
+	definitions := map[string]Object{typeName.Value: recordType}
+	procedures := []*Identifier{}
+
+	for _, spec := range args[3:] {
+		field, err := listToSlice(spec)
+		if err != nil || len(field) < 2 || len(field) > 3 {
+			return malformed, nil
+		}
+
+		for _, obj := range field {
+			ident, ok := obj.(*Identifier)
+			if !ok {
+				return malformed, nil
+			}
+
+			procedures = append(procedures, ident)
+		}
+
+		index := len(recordType.Fields)
+		recordType.Fields = append(recordType.Fields, field[0].(*Identifier).Value)
+		definitions[field[1].(*Identifier).Value] = recordAccessor(recordType, field[1].(*Identifier).Value, index)
+
+		if len(field) == 3 {
+			definitions[field[2].(*Identifier).Value] = recordModifier(recordType, field[2].(*Identifier).Value, index)
+		}
+	}
+
+	switch constructor := args[1].(type) {
+	case *Identifier:
+		indexes := make([]int, len(recordType.Fields))
+		for idx := range indexes {
+			indexes[idx] = idx
+		}
+
+		definitions[constructor.Value] = recordConstructor(recordType, constructor.Value, indexes)
+	case *Pair:
+		spec, err := listToSlice(constructor)
+		if err != nil {
+			return malformed, nil
+		}
+
+		name, ok := spec[0].(*Identifier)
+		if !ok {
+			return malformed, nil
+		}
+
+		indexes := []int{}
+		for _, obj := range spec[1:] {
+			index := -1
+			if ident, ok := obj.(*Identifier); ok {
+				index = recordType.fieldIndex(ident.Value)
+			}
+
+			if index < 0 {
+				return errorObject(fmt.Errorf("DEFINE-RECORD-TYPE constructor field %s is not a field of %s", obj.Inspect(), recordType.Name)), nil
+			}
+
+			indexes = append(indexes, index)
+		}
+
+		definitions[name.Value] = recordConstructor(recordType, name.Value, indexes)
+	default:
+		if args[1] != FALSE {
+			return malformed, nil
+		}
+	}
+
+	switch predicate := args[2].(type) {
+	case *Identifier:
+		definitions[predicate.Value] = recordPredicate(recordType, predicate.Value)
+	default:
+		if args[2] != FALSE {
+			return malformed, nil
+		}
+	}
+
+	for name, value := range definitions {
+		env.Set(name, value)
+	}
+
+	return UNSPECIFIED, nil
+}
+
+// recordConstructor fills the fields at indexes from its arguments, the
+// remaining fields start out unspecified
+func recordConstructor(recordType *RecordType, name string, indexes []int) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs(name, args, len(indexes)); err != nil {
+				return err
+			}
+
+			record := &Record{Type: recordType, Values: make([]Object, len(recordType.Fields))}
+			for index := range record.Values {
+				record.Values[index] = UNSPECIFIED
+			}
+
+			for idx, index := range indexes {
+				record.Values[index] = args[idx]
+			}
+
+			return record
+		},
+	}
+}
+
+// recordPredicate checks for records of recordType
+func recordPredicate(recordType *RecordType, name string) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs(name, args, 1); err != nil {
+				return err
+			}
+
+			record, ok := args[0].(*Record)
+			return toBoolean(ok && record.Type == recordType)
+		},
+	}
+}
+
+// recordAccessor returns the field at index
+func recordAccessor(recordType *RecordType, name string, index int) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs(name, args, 1); err != nil {
+				return err
+			}
+
+			record, err := expectRecord(name, recordType, args[0])
+			if err != nil {
+				return err
+			}
+
+			return record.Values[index]
+		},
+	}
+}
+
+// recordModifier sets the field at index
+func recordModifier(recordType *RecordType, name string, index int) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs(name, args, 2); err != nil {
+				return err
+			}
+
+			record, err := expectRecord(name, recordType, args[0])
+			if err != nil {
+				return err
+			}
+
+			record.Values[index] = args[1]
+			return UNSPECIFIED
+		},
+	}
+}
+
+// expectRecord checks obj is a record of recordType
+func expectRecord(name string, recordType *RecordType, obj Object) (*Record, *Error) {
+	record, ok := obj.(*Record)
+	if !ok || record.Type != recordType {
+		return nil, errorObject(fmt.Errorf("%s expecting a record of type %s found %s", name, recordType.Name, obj.Inspect()))
+	}
+
+	return record, nil
+}
+
+// fieldIndex returns the position of field or -1
+func (t *RecordType) fieldIndex(field string) int {
+	for idx, name := range t.Fields {
+		if name == field {
+			return idx
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// charNames are the named character literals such as #\space
+var charNames = []struct {
+	name  string
+	value rune
+}{
+	{"alarm", 7},
+	{"backspace", 8},
+	{"delete", 127},
+	{"escape", 27},
+	{"newline", '\n'},
+	{"null", 0},
+	{"return", '\r'},
+	{"space", ' '},
+	{"tab", '\t'},
+}
+
+// charByName looks up a character name ignoring case
+func charByName(name string) (rune, bool) {
+	for _, named := range charNames {
+		if strings.EqualFold(named.name, name) {
+			return named.value, true
+		}
+	}
+
+	return 0, false
+}
+
+// charName returns the name a character is written with
+func charName(value rune) (string, bool) {
+	for _, named := range charNames {
+		if named.value == value {
+			return named.name, true
+		}
+	}
+
+	return "", false
+}
+
+// foldRune applies simple case folding
+func foldRune(char rune) rune {
+	return unicode.ToLower(unicode.ToUpper(char))
+}
+
+// foldString applies simple case folding to every character
+func foldString(str string) string {
+	return strings.Map(foldRune, str)
+}
+
+// expectString checks obj is a string
+func expectString(name string, obj Object) (*String, *Error) {
+	str, ok := obj.(*String)
+	if !ok {
+		return nil, errorObject(fmt.Errorf("%s expecting a string found %s", name, obj.Inspect()))
+	}
+
+	return str, nil
+}
+
+// expectChar checks obj is a character
+func expectChar(name string, obj Object) (*Char, *Error) {
+	char, ok := obj.(*Char)
+	if !ok {
+		return nil, errorObject(fmt.Errorf("%s expecting a character found %s", name, obj.Inspect()))
+	}
+
+	return char, nil
+}
+
+// expectBounds checks index is within length
+func expectBounds(name string, obj Object, length int) (int, *Error) {
+	index, err := expectIndex(name, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	if index >= length {
+		return 0, errorObject(fmt.Errorf("%s index %d out of range for length %d", name, index, length))
+	}
+
+	return index, nil
+}
+
+// expectRange reads the optional start and end arguments of a sequence of
+// length, they default to the whole sequence
+func expectRange(name string, args []Object, length int) (int, int, *Error) {
+	start, end := 0, length
+
+	if len(args) > 2 {
+		return 0, 0, errorObject(fmt.Errorf("%s expecting at most a start and an end index", name))
+	}
+
+	if len(args) > 0 {
+		index, err := expectIndex(name, args[0])
+		if err != nil {
+			return 0, 0, err
+		}
+
+		start = index
+	}
+
+	if len(args) > 1 {
+		index, err := expectIndex(name, args[1])
+		if err != nil {
+			return 0, 0, err
+		}
+
+		end = index
+	}
+
+	if start > end || end > length {
+		return 0, 0, errorObject(fmt.Errorf("%s range %d to %d out of bounds for length %d", name, start, end, length))
+	}
+
+	return start, end, nil
+}
+
+// stringComparison creates a STRING=? like procedure where test checks the
+// result of comparing each pair of neighbouring strings
+func stringComparison(name string, fold bool, test func(int) bool) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			strs := []string{}
+
+			for _, arg := range args {
+				str, err := expectString(name, arg)
+				if err != nil {
+					return err
+				}
+
+				if fold {
+					strs = append(strs, foldString(str.Value))
+				} else {
+					strs = append(strs, str.Value)
+				}
+			}
+
+			for idx := 1; idx < len(strs); idx++ {
+				if !test(strings.Compare(strs[idx-1], strs[idx])) {
+					return FALSE
+				}
+			}
+
+			return TRUE
+		},
+	}
+}
+
+// charComparison creates a CHAR=? like procedure where test checks the
+// difference of each pair of neighbouring characters
+func charComparison(name string, fold bool, test func(int) bool) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			chars := []rune{}
+
+			for _, arg := range args {
+				char, err := expectChar(name, arg)
+				if err != nil {
+					return err
+				}
+
+				if fold {
+					chars = append(chars, foldRune(char.Value))
+				} else {
+					chars = append(chars, char.Value)
+				}
+			}
+
+			for idx := 1; idx < len(chars); idx++ {
+				if !test(int(chars[idx-1]) - int(chars[idx])) {
+					return FALSE
+				}
+			}
+
+			return TRUE
+		},
+	}
+}
+
+// charPredicate creates a procedure testing a single character
+func charPredicate(name string, test func(rune) bool) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs(name, args, 1); err != nil {
+				return err
+			}
+
+			char, err := expectChar(name, args[0])
+			if err != nil {
+				return err
+			}
+
+			return toBoolean(test(char.Value))
+		},
+	}
+}
+
+// charMapping creates a procedure converting a single character
+func charMapping(name string, fn func(rune) rune) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs(name, args, 1); err != nil {
+				return err
+			}
+
+			char, err := expectChar(name, args[0])
+			if err != nil {
+				return err
+			}
+
+			return &Char{Value: fn(char.Value)}
+		},
+	}
+}
+
+// stringMapping creates a procedure returning a converted copy of a string
+func stringMapping(name string, fn func(string) string) *Builtin {
+	return &Builtin{
+		Fn: func(args ...Object) Object {
+			if err := expectArgs(name, args, 1); err != nil {
+				return err
+			}
+
+			str, err := expectString(name, args[0])
+			if err != nil {
+				return err
+			}
+
+			return &String{Value: fn(str.Value)}
+		},
+	}
+}
+
+// writeString quotes str escaping the characters that need it
+func writeString(str string) string {
+	escaped := strings.Builder{}
+	escaped.WriteByte('"')
+
+	for _, char := range str {
+		switch char {
+		case '"':
+			escaped.WriteString("\\\"")
+		case '\\':
+			escaped.WriteString("\\\\")
+		case '\n':
+			escaped.WriteString("\\n")
+		case '\t':
+			escaped.WriteString("\\t")
+		case '\r':
+			escaped.WriteString("\\r")
+		default:
+			escaped.WriteRune(char)
+		}
+	}
+
+	escaped.WriteByte('"')
+	return escaped.String()
+}